@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/awade12/spindb/internal/agent"
+	"github.com/awade12/spindb/internal/secrets"
+	"github.com/awade12/spindb/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Run scheduled backups in the foreground",
+	Long: `Run the backup agent in the foreground until interrupted.
+
+The agent backs up each database whenever its schedule matches (see 'spindb backup schedule'),
+applies the retention policy afterwards and logs the results. Check on it with 'spindb backup status'.
+To keep it running, install it as a systemd user service:
+
+  spindb agent systemd-unit > ~/.config/systemd/user/spindb-agent.service
+  systemctl --user enable --now spindb-agent
+
+systemd does not pass on your shell's environment. If the vault uses a passphrase, put
+SPINDB_VAULT_PASSPHRASE=... in $SPINDB_HOME/agent.env, readable only by you, or every backup fails.`,
+	Args: cobra.NoArgs,
+	RunE: runAgent,
+}
+
+var agentSystemdUnitCmd = &cobra.Command{
+	Use:   "systemd-unit",
+	Short: "Print a systemd user unit for the agent",
+	Long: `Print a systemd user unit that runs 'spindb agent' with the current SpinDB home,
+--config, --data-dir and --backup-dir. The unit reads extra environment, such as
+SPINDB_VAULT_PASSPHRASE, from $SPINDB_HOME/agent.env when that file exists.`,
+	Args: cobra.NoArgs,
+	RunE: printSystemdUnit,
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+	agentCmd.AddCommand(agentSystemdUnitCmd)
+}
+
+func runAgent(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return agent.New().Run(ctx)
+}
+
+func printSystemdUnit(cmd *cobra.Command, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the spindb executable: %w", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return fmt.Errorf("failed to locate the spindb executable: %w", err)
+	}
+
+	command := []string{executable, "agent"}
+	for _, flag := range []string{"config", "data-dir", "backup-dir"} {
+		value, _ := cmd.Flags().GetString(flag)
+		if value == "" {
+			continue
+		}
+		if value, err = filepath.Abs(value); err != nil {
+			return fmt.Errorf("failed to resolve --%s: %w", flag, err)
+		}
+		command = append(command, "--"+flag, value)
+	}
+
+	home := utils.SpinDBHome()
+	envFile := filepath.Join(home, "agent.env")
+
+	fmt.Printf(`[Unit]
+Description=SpinDB backup agent
+
+[Service]
+ExecStart=%s
+Environment=SPINDB_HOME=%s
+# Put SPINDB_VAULT_PASSPHRASE=... here when the vault uses a passphrase.
+EnvironmentFile=-%s
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=default.target
+`, strings.Join(command, " "), home, envFile)
+
+	if os.Getenv(secrets.PassphraseEnv) != "" {
+		if _, err := os.Stat(envFile); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %s is set here but systemd will not see it. Add it to %s (chmod 600) before starting the unit.\n", secrets.PassphraseEnv, envFile)
+		}
+	}
+	return nil
+}
@@ -3,10 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
+	"sort"
 	"text/tabwriter"
 
 	"github.com/awade12/spindb/internal/backup"
+	"github.com/awade12/spindb/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -34,29 +35,45 @@ var backupListCmd = &cobra.Command{
 var backupRestoreCmd = &cobra.Command{
 	Use:   "restore [backup-name] [target-database]",
 	Short: "Restore a backup to a database",
-	Long:  `Restore a backup to the specified target database`,
+	Long:  `Restore a backup to the specified target database after checking it against its manifest checksum`,
 	Args:  cobra.ExactArgs(2),
 	RunE:  restoreBackup,
 }
 
+var backupVerifyCmd = &cobra.Command{
+	Use:   "verify [backup-name]",
+	Short: "Test-restore a backup into a scratch instance",
+	Long: `Check the backup's checksum, restore it into a throwaway instance of the recorded engine and version,
+count the rows of every table and run any --assert queries, then remove the instance.
+The result is recorded on the backup's manifest.`,
+	Args: cobra.ExactArgs(1),
+	RunE: verifyBackup,
+}
+
 var backupDeleteCmd = &cobra.Command{
 	Use:   "delete [backup-name]",
 	Short: "Delete a backup",
-	Long:  `Delete the specified backup file`,
+	Long:  `Delete the specified backup file and its manifest`,
 	Args:  cobra.ExactArgs(1),
 	RunE:  deleteBackup,
 }
 
 func init() {
+	backup.SpinDBVersion = Version
+
 	rootCmd.AddCommand(backupCmd)
 	backupCmd.AddCommand(backupCreateCmd)
 	backupCmd.AddCommand(backupListCmd)
 	backupCmd.AddCommand(backupRestoreCmd)
+	backupCmd.AddCommand(backupVerifyCmd)
 	backupCmd.AddCommand(backupDeleteCmd)
 
 	backupCreateCmd.Flags().Bool("compress", false, "Compress the backup file")
 	backupCreateCmd.Flags().Bool("schema-only", false, "Backup schema only (no data)")
 	backupCreateCmd.Flags().Bool("data-only", false, "Backup data only (no schema)")
+	backupCreateCmd.Flags().Bool("prune", false, "Apply the retention policy to the database's backups afterwards (default from backup.auto_prune)")
+
+	backupVerifyCmd.Flags().StringArray("assert", nil, "SQL query whose first value must be true or non-zero (repeatable)")
 }
 
 func createBackup(cmd *cobra.Command, args []string) error {
@@ -92,6 +109,21 @@ func createBackup(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Compressed: Yes\n")
 	}
 
+	prune := config.Load().Backup.AutoPrune
+	if cmd.Flags().Changed("prune") {
+		prune, _ = cmd.Flags().GetBool("prune")
+	}
+
+	if prune {
+		pruned, err := manager.Prune(dbName, false)
+		if err != nil {
+			return fmt.Errorf("failed to prune backups: %w", err)
+		}
+		for _, backup := range pruned {
+			fmt.Printf("   Pruned: %s\n", backup.Name)
+		}
+	}
+
 	return nil
 }
 
@@ -103,45 +135,52 @@ func listBackups(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list backups: %w", err)
 	}
 
-	if len(backups) == 0 {
-		fmt.Println("No backups found.")
-		return nil
-	}
-
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NAME\tDATABASE\tTYPE\tSIZE\tCREATED\tCOMPRESSED")
-	fmt.Fprintln(w, "----\t--------\t----\t----\t-------\t----------")
-
-	for _, backup := range backups {
-		size := fmt.Sprintf("%.2f MB", float64(backup.Size)/(1024*1024))
-		compressed := "No"
-		if backup.Compressed {
-			compressed = "Yes"
+	return render(backups, func() error {
+		if len(backups) == 0 {
+			fmt.Println("No backups found.")
+			return nil
 		}
 
-		created := backup.CreatedAt.Format("2006-01-02 15:04")
-
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			backup.Name,
-			backup.Database,
-			backup.Type,
-			size,
-			created,
-			compressed,
-		)
-	}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDATABASE\tTYPE\tSIZE\tCREATED\tCOMPRESSED\tVERIFIED")
+		fmt.Fprintln(w, "----\t--------\t----\t----\t-------\t----------\t--------")
+
+		for _, backup := range backups {
+			size := fmt.Sprintf("%.2f MB", float64(backup.Size)/(1024*1024))
+			compressed := "No"
+			if backup.Compressed {
+				compressed = "Yes"
+			}
+
+			created := backup.CreatedAt.Format("2006-01-02 15:04")
+
+			verified := "-"
+			if v := backup.Verification; v != nil {
+				verified = "❌ " + v.VerifiedAt.Format("2006-01-02 15:04")
+				if v.Passed {
+					verified = "✅ " + v.VerifiedAt.Format("2006-01-02 15:04")
+				}
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				backup.Name,
+				backup.Database,
+				backup.Type,
+				size,
+				created,
+				compressed,
+				verified,
+			)
+		}
 
-	return w.Flush()
+		return w.Flush()
+	})
 }
 
 func restoreBackup(cmd *cobra.Command, args []string) error {
 	backupName := args[0]
 	targetDb := args[1]
 
-	if !strings.Contains(backupName, ".") {
-		return fmt.Errorf("backup name must include file extension (e.g., backup_name.sql)")
-	}
-
 	manager := backup.NewBackupManager()
 
 	fmt.Printf("Restoring backup '%s' to database '%s'...\n", backupName, targetDb)
@@ -154,6 +193,69 @@ func restoreBackup(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func verifyBackup(cmd *cobra.Command, args []string) error {
+	assertions, _ := cmd.Flags().GetStringArray("assert")
+
+	manager := backup.NewBackupManager()
+
+	fmt.Printf("Verifying backup '%s'...\n", args[0])
+	verification, err := manager.VerifyBackup(args[0], assertions)
+	if err != nil {
+		return fmt.Errorf("failed to verify backup: %w", err)
+	}
+
+	err = render(verification, func() error {
+		if verification.ChecksumOK {
+			fmt.Println("   Checksum: OK")
+		} else {
+			fmt.Println("   Checksum: MISMATCH")
+		}
+
+		if verification.Restored {
+			fmt.Printf("   Restore: OK (%d tables)\n", len(verification.Tables))
+		}
+
+		tables := make([]string, 0, len(verification.Tables))
+		for table := range verification.Tables {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		for _, table := range tables {
+			fmt.Printf("      %s: %d rows\n", table, verification.Tables[table])
+		}
+
+		for _, assertion := range verification.Assertions {
+			status := "✅"
+			if !assertion.Passed {
+				status = "❌"
+			}
+			fmt.Printf("   %s %s", status, assertion.Query)
+			if assertion.Error != "" {
+				fmt.Printf(" (%s)", assertion.Error)
+			}
+			fmt.Println()
+		}
+
+		if verification.Error != "" {
+			fmt.Printf("   Error: %s\n", verification.Error)
+		}
+
+		if verification.Passed {
+			fmt.Printf("✅ Backup '%s' verified successfully!\n", args[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !verification.Passed {
+		return fmt.Errorf("backup '%s' failed verification", args[0])
+	}
+
+	return nil
+}
+
 func deleteBackup(cmd *cobra.Command, args []string) error {
 	backupName := args[0]
 
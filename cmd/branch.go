@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awade12/spindb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var branchCmd = &cobra.Command{
+	Use:   "branch [database-name] [branch-name]",
+	Short: "Fork a database into a new branch instance",
+	Long:  `Create a new instance whose data directory is a copy-on-write copy of the parent at this moment`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  branchDatabase,
+}
+
+var branchListCmd = &cobra.Command{
+	Use:   "list [database-name]",
+	Short: "Show the branch tree of a database",
+	Long:  `Show the branches forked from a database and their own branches`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  listBranches,
+}
+
+func init() {
+	rootCmd.AddCommand(branchCmd)
+	branchCmd.AddCommand(branchListCmd)
+
+	branchCmd.Flags().IntP("port", "", 0, "Port for the branch (0 for auto)")
+	branchCmd.Flags().Bool("public", false, "Make the branch publicly accessible")
+}
+
+func branchDatabase(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	return manager.Branch(args[0], args[1], &db.BranchOptions{
+		Port:   port,
+		Public: public,
+	})
+}
+
+func listBranches(cmd *cobra.Command, args []string) error {
+	manager := db.NewManager()
+	branches, err := manager.ListBranches(args[0])
+	if err != nil {
+		return err
+	}
+
+	return render(branches, func() error {
+		root := branches[0]
+		if root.Parent != "" {
+			fmt.Printf("Branched from: %s\n\n", root.Parent)
+		}
+
+		fmt.Printf("🌳 %s (%s)\n", root.Name, root.Type)
+		printBranches(branches[1:], root.Name, "")
+
+		if len(branches) == 1 {
+			fmt.Printf("\nNo branches found. Create one with: spindb branch %s <branch-name>\n", root.Name)
+		}
+		return nil
+	})
+}
+
+// printBranches draws the children of parent as a tree. branches is in the
+// depth-first order returned by ListBranches.
+func printBranches(branches []db.DatabaseInfo, parent, indent string) {
+	var children []db.DatabaseInfo
+	for _, branch := range branches {
+		if branch.Parent == parent {
+			children = append(children, branch)
+		}
+	}
+
+	for i, branch := range children {
+		connector, childIndent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, childIndent = "└── ", "    "
+		}
+
+		fmt.Printf("%s%s%s (created %s)\n", indent, connector, branch.Name, branch.Created.Format("2006-01-02 15:04"))
+		printBranches(branches, branch.Name, indent+childIndent)
+	}
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/awade12/spindb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone [source-database] [new-database]",
+	Short: "Clone a database into a new instance",
+	Long: `Provision a new instance of the same type and version as the source and stream its data into it.
+Engines without dump support (ClickHouse, SQL Server) are cloned by copying the
+data directory while the source is stopped.`,
+	Args: cobra.ExactArgs(2),
+	RunE: cloneDatabase,
+}
+
+func init() {
+	rootCmd.AddCommand(cloneCmd)
+	cloneCmd.Flags().IntP("port", "", 0, "Port for the clone (0 for auto)")
+	cloneCmd.Flags().Bool("public", false, "Make the clone publicly accessible")
+}
+
+func cloneDatabase(cmd *cobra.Command, args []string) error {
+	port, _ := cmd.Flags().GetInt("port")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	return manager.Clone(args[0], args[1], &db.CloneOptions{
+		Port:   port,
+		Public: public,
+	})
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit SpinDB configuration",
+	Long: `Inspect and edit SpinDB configuration.
+
+Settings are layered, later sources winning: built-in defaults, the global
+config file, a .spindb.yaml in the current directory, SPINDB_* environment
+variables (e.g. SPINDB_STORAGE_DATA_DIR) and command-line flags.`,
+	// Config commands run with an invalid configuration, so it can be fixed.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return validateOutput(cmd) },
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Show the effective value of a setting",
+	Long:  `Show the effective value of a setting, e.g. default.postgres.version`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  getConfig,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Change a setting in a config file",
+	Long:  `Write a setting to the global config file, or to the project file with --project`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  setConfig,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all effective settings",
+	Long:  `List every setting with its effective value after all layers are applied`,
+	RunE:  listConfig,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show config file locations",
+	Long:  `Show where the global and project config files live and which were loaded`,
+	RunE:  showConfigPath,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configPathCmd)
+
+	configSetCmd.Flags().Bool("project", false, "Write to .spindb.yaml in the current directory")
+}
+
+func getConfig(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	if !config.IsKey(key) {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+
+	value := viper.Get(key)
+	return render(value, func() error {
+		fmt.Println(value)
+		return nil
+	})
+}
+
+func setConfig(cmd *cobra.Command, args []string) error {
+	project, _ := cmd.Flags().GetBool("project")
+
+	path := configFilePath()
+	if project {
+		path = config.ProjectConfigPath()
+	}
+
+	if err := config.SetInFile(path, args[0], args[1]); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Set %s = %s in %s\n", args[0], args[1], path)
+	return nil
+}
+
+func listConfig(cmd *cobra.Command, args []string) error {
+	settings := make(map[string]any)
+	for _, key := range config.Keys() {
+		settings[key] = viper.Get(key)
+	}
+
+	return render(settings, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "KEY\tVALUE")
+		fmt.Fprintln(w, "---\t-----")
+
+		for _, key := range config.Keys() {
+			fmt.Fprintf(w, "%s\t%v\n", key, settings[key])
+		}
+
+		return w.Flush()
+	})
+}
+
+func showConfigPath(cmd *cobra.Command, args []string) error {
+	for _, file := range []struct {
+		label string
+		path  string
+	}{
+		{"Global", configFilePath()},
+		{"Project", config.ProjectConfigPath()},
+	} {
+		status := "not found"
+		if slices.Contains(loadedConfigFiles, file.path) {
+			status = "loaded"
+		}
+		fmt.Printf("%-8s %s (%s)\n", file.label+":", file.path, status)
+	}
+
+	return nil
+}
+
+func configFilePath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	return config.GlobalConfigPath()
+}
@@ -10,7 +10,7 @@ import (
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new database instance",
-	Long:  `Create and configure a new database instance (PostgreSQL, MySQL, or SQLite)`,
+	Long:  `Create and configure a new database instance (PostgreSQL, MySQL, MariaDB, SQL Server, MongoDB, Redis, ClickHouse, or SQLite)`,
 }
 
 var createPostgresCmd = &cobra.Command{
@@ -27,6 +27,41 @@ var createMysqlCmd = &cobra.Command{
 	RunE:  createMysql,
 }
 
+var createClickhouseCmd = &cobra.Command{
+	Use:   "clickhouse",
+	Short: "Create a ClickHouse database",
+	Long:  `Create and start a ClickHouse server using Docker, exposing the HTTP and native ports`,
+	RunE:  createClickhouse,
+}
+
+var createMssqlCmd = &cobra.Command{
+	Use:   "mssql",
+	Short: "Create a SQL Server database",
+	Long:  `Create and start a Microsoft SQL Server (Linux container) instance using Docker`,
+	RunE:  createMssql,
+}
+
+var createMariadbCmd = &cobra.Command{
+	Use:   "mariadb",
+	Short: "Create a MariaDB database",
+	Long:  `Create and start a MariaDB database instance using Docker`,
+	RunE:  createMariadb,
+}
+
+var createMongoCmd = &cobra.Command{
+	Use:   "mongo",
+	Short: "Create a MongoDB database",
+	Long:  `Create and start a MongoDB database instance using Docker`,
+	RunE:  createMongo,
+}
+
+var createRedisCmd = &cobra.Command{
+	Use:   "redis",
+	Short: "Create a Redis database",
+	Long:  `Create and start a Redis instance using Docker`,
+	RunE:  createRedis,
+}
+
 var createSqliteCmd = &cobra.Command{
 	Use:   "sqlite",
 	Short: "Create a SQLite database",
@@ -38,25 +73,67 @@ func init() {
 	rootCmd.AddCommand(createCmd)
 	createCmd.AddCommand(createPostgresCmd)
 	createCmd.AddCommand(createMysqlCmd)
+	createCmd.AddCommand(createClickhouseCmd)
+	createCmd.AddCommand(createMariadbCmd)
+	createCmd.AddCommand(createMssqlCmd)
+	createCmd.AddCommand(createMongoCmd)
+	createCmd.AddCommand(createRedisCmd)
 	createCmd.AddCommand(createSqliteCmd)
 
 	createPostgresCmd.Flags().StringP("name", "n", "", "Database name (required)")
-	createPostgresCmd.Flags().StringP("user", "u", "postgres", "Database user")
-	createPostgresCmd.Flags().StringP("password", "p", "", "Database password (required)")
+	createPostgresCmd.Flags().StringP("user", "u", "", "Database user (default from default.postgres.user in config)")
+	createPostgresCmd.Flags().StringP("password", "p", "", "Database password (generated when omitted)")
 	createPostgresCmd.Flags().IntP("port", "", 0, "Database port (0 for auto)")
-	createPostgresCmd.Flags().StringP("version", "v", "15", "PostgreSQL version")
+	createPostgresCmd.Flags().StringP("version", "v", "", "PostgreSQL version (default from default.postgres.version in config)")
 	createPostgresCmd.Flags().Bool("public", false, "Make database publicly accessible")
+	createPostgresCmd.Flags().StringSlice("extensions", []string{}, "Extensions to enable (postgis, timescaledb, vector)")
 	createPostgresCmd.MarkFlagRequired("name")
-	createPostgresCmd.MarkFlagRequired("password")
 
 	createMysqlCmd.Flags().StringP("name", "n", "", "Database name (required)")
-	createMysqlCmd.Flags().StringP("user", "u", "root", "Database user")
-	createMysqlCmd.Flags().StringP("password", "p", "", "Database password (required)")
+	createMysqlCmd.Flags().StringP("user", "u", "", "Database user (default from default.mysql.user in config)")
+	createMysqlCmd.Flags().StringP("password", "p", "", "Database password (generated when omitted)")
 	createMysqlCmd.Flags().IntP("port", "", 0, "Database port (0 for auto)")
-	createMysqlCmd.Flags().StringP("version", "v", "8.0", "MySQL version")
+	createMysqlCmd.Flags().StringP("version", "v", "", "MySQL version (default from default.mysql.version in config)")
 	createMysqlCmd.Flags().Bool("public", false, "Make database publicly accessible")
 	createMysqlCmd.MarkFlagRequired("name")
-	createMysqlCmd.MarkFlagRequired("password")
+
+	createClickhouseCmd.Flags().StringP("name", "n", "", "Database name (required)")
+	createClickhouseCmd.Flags().StringP("user", "u", "", "Database user (default from default.clickhouse.user in config)")
+	createClickhouseCmd.Flags().StringP("password", "p", "", "Database password (generated when omitted)")
+	createClickhouseCmd.Flags().IntP("port", "", 0, "HTTP port (0 for auto)")
+	createClickhouseCmd.Flags().StringP("version", "v", "", "ClickHouse version (default from default.clickhouse.version in config)")
+	createClickhouseCmd.Flags().Bool("public", false, "Make database publicly accessible")
+	createClickhouseCmd.MarkFlagRequired("name")
+
+	createMariadbCmd.Flags().StringP("name", "n", "", "Database name (required)")
+	createMariadbCmd.Flags().StringP("user", "u", "", "Database user (default from default.mariadb.user in config)")
+	createMariadbCmd.Flags().StringP("password", "p", "", "Database password (generated when omitted)")
+	createMariadbCmd.Flags().IntP("port", "", 0, "Database port (0 for auto)")
+	createMariadbCmd.Flags().StringP("version", "v", "", "MariaDB version (default from default.mariadb.version in config)")
+	createMariadbCmd.Flags().Bool("public", false, "Make database publicly accessible")
+	createMariadbCmd.MarkFlagRequired("name")
+
+	createMssqlCmd.Flags().StringP("name", "n", "", "Database name (required)")
+	createMssqlCmd.Flags().StringP("password", "p", "", "SA password, must meet SQL Server complexity rules (generated when omitted)")
+	createMssqlCmd.Flags().IntP("port", "", 0, "Database port (0 for auto)")
+	createMssqlCmd.Flags().StringP("version", "v", "", "SQL Server image tag (default from default.mssql.version in config)")
+	createMssqlCmd.Flags().Bool("public", false, "Make database publicly accessible")
+	createMssqlCmd.MarkFlagRequired("name")
+
+	createMongoCmd.Flags().StringP("name", "n", "", "Database name (required)")
+	createMongoCmd.Flags().StringP("user", "u", "", "Root user (default from default.mongo.user in config)")
+	createMongoCmd.Flags().StringP("password", "p", "", "Root password (generated when omitted)")
+	createMongoCmd.Flags().IntP("port", "", 0, "Database port (0 for auto)")
+	createMongoCmd.Flags().StringP("version", "v", "", "MongoDB version (default from default.mongo.version in config)")
+	createMongoCmd.Flags().Bool("public", false, "Make database publicly accessible")
+	createMongoCmd.MarkFlagRequired("name")
+
+	createRedisCmd.Flags().StringP("name", "n", "", "Database name (required)")
+	createRedisCmd.Flags().StringP("password", "p", "", "Redis password (generated when omitted)")
+	createRedisCmd.Flags().IntP("port", "", 0, "Database port (0 for auto)")
+	createRedisCmd.Flags().StringP("version", "v", "", "Redis version (default from default.redis.version in config)")
+	createRedisCmd.Flags().Bool("public", false, "Make database publicly accessible")
+	createRedisCmd.MarkFlagRequired("name")
 
 	createSqliteCmd.Flags().StringP("file", "f", "", "SQLite database file path (required)")
 	createSqliteCmd.MarkFlagRequired("file")
@@ -69,15 +146,17 @@ func createPostgres(cmd *cobra.Command, args []string) error {
 	port, _ := cmd.Flags().GetInt("port")
 	version, _ := cmd.Flags().GetString("version")
 	public, _ := cmd.Flags().GetBool("public")
+	extensions, _ := cmd.Flags().GetStringSlice("extensions")
 
 	manager := db.NewManager()
 	config := &db.PostgresConfig{
-		Name:     name,
-		User:     user,
-		Password: password,
-		Port:     port,
-		Version:  version,
-		Public:   public,
+		Name:       name,
+		User:       user,
+		Password:   password,
+		Port:       port,
+		Version:    version,
+		Public:     public,
+		Extensions: extensions,
 	}
 
 	fmt.Printf("Creating PostgreSQL database '%s'...\n", name)
@@ -106,6 +185,113 @@ func createMysql(cmd *cobra.Command, args []string) error {
 	return manager.CreateMySQL(config)
 }
 
+func createClickhouse(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	user, _ := cmd.Flags().GetString("user")
+	password, _ := cmd.Flags().GetString("password")
+	port, _ := cmd.Flags().GetInt("port")
+	version, _ := cmd.Flags().GetString("version")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	config := &db.ClickHouseConfig{
+		Name:     name,
+		User:     user,
+		Password: password,
+		Port:     port,
+		Version:  version,
+		Public:   public,
+	}
+
+	fmt.Printf("Creating ClickHouse database '%s'...\n", name)
+	return manager.CreateClickHouse(config)
+}
+
+func createMariadb(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	user, _ := cmd.Flags().GetString("user")
+	password, _ := cmd.Flags().GetString("password")
+	port, _ := cmd.Flags().GetInt("port")
+	version, _ := cmd.Flags().GetString("version")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	config := &db.MariaDBConfig{
+		Name:     name,
+		User:     user,
+		Password: password,
+		Port:     port,
+		Version:  version,
+		Public:   public,
+	}
+
+	fmt.Printf("Creating MariaDB database '%s'...\n", name)
+	return manager.CreateMariaDB(config)
+}
+
+func createMssql(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	password, _ := cmd.Flags().GetString("password")
+	port, _ := cmd.Flags().GetInt("port")
+	version, _ := cmd.Flags().GetString("version")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	config := &db.MSSQLConfig{
+		Name:     name,
+		User:     "sa",
+		Password: password,
+		Port:     port,
+		Version:  version,
+		Public:   public,
+	}
+
+	fmt.Printf("Creating SQL Server database '%s'...\n", name)
+	return manager.CreateMSSQL(config)
+}
+
+func createMongo(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	user, _ := cmd.Flags().GetString("user")
+	password, _ := cmd.Flags().GetString("password")
+	port, _ := cmd.Flags().GetInt("port")
+	version, _ := cmd.Flags().GetString("version")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	config := &db.MongoConfig{
+		Name:     name,
+		User:     user,
+		Password: password,
+		Port:     port,
+		Version:  version,
+		Public:   public,
+	}
+
+	fmt.Printf("Creating MongoDB database '%s'...\n", name)
+	return manager.CreateMongo(config)
+}
+
+func createRedis(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	password, _ := cmd.Flags().GetString("password")
+	port, _ := cmd.Flags().GetInt("port")
+	version, _ := cmd.Flags().GetString("version")
+	public, _ := cmd.Flags().GetBool("public")
+
+	manager := db.NewManager()
+	config := &db.RedisConfig{
+		Name:     name,
+		Password: password,
+		Port:     port,
+		Version:  version,
+		Public:   public,
+	}
+
+	fmt.Printf("Creating Redis database '%s'...\n", name)
+	return manager.CreateRedis(config)
+}
+
 func createSqlite(cmd *cobra.Command, args []string) error {
 	file, _ := cmd.Flags().GetString("file")
 
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/awade12/spindb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Manage database credentials",
+	Long:  `Manage the credentials SpinDB uses for its database instances`,
+}
+
+var credentialsRotateCmd = &cobra.Command{
+	Use:   "rotate [database-name]",
+	Short: "Rotate a database password",
+	Long:  `Change the password of a running database, update the registry and secrets store, and print the new connection string`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  rotateCredentials,
+}
+
+func init() {
+	rootCmd.AddCommand(credentialsCmd)
+	credentialsCmd.AddCommand(credentialsRotateCmd)
+	credentialsRotateCmd.Flags().StringP("password", "p", "", "New password (generated when omitted)")
+}
+
+func rotateCredentials(cmd *cobra.Command, args []string) error {
+	password, _ := cmd.Flags().GetString("password")
+
+	manager := db.NewManager()
+	return manager.RotateCredentials(args[0], password)
+}
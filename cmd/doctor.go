@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awade12/spindb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the registry against Docker and the data directories",
+	Long:  `Cross-check registered databases, SpinDB containers and data directories and report any drift between them`,
+	RunE:  runDoctor,
+}
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Fix drift between the registry and Docker",
+	Long: `Fix the issues reported by 'spindb doctor': re-create containers from their preserved data directory and adopt unregistered containers.
+With --prune, also remove registry entries and containers that cannot be recovered.
+Data directories left by delete or down are only removed with --remove-orphaned-data,
+and each one is confirmed even with --yes.`,
+	RunE: runReconcile,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(reconcileCmd)
+
+	reconcileCmd.Flags().Bool("prune", false, "Also remove orphaned registry entries and containers")
+	reconcileCmd.Flags().Bool("remove-orphaned-data", false, "Also offer to delete data directories no database uses")
+	reconcileCmd.Flags().BoolP("yes", "y", false, "Apply every fix without asking, except deleting data directories")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	manager := db.NewManager()
+	issues, err := manager.Doctor()
+	if err != nil {
+		return err
+	}
+
+	return render(issues, func() error {
+		if len(issues) == 0 {
+			fmt.Println("✅ Registry, containers and data directories are in sync.")
+			return nil
+		}
+
+		for _, issue := range issues {
+			printIssue(issue)
+		}
+
+		fmt.Printf("\n%d issues found. Run 'spindb reconcile' to fix them.\n", len(issues))
+		return nil
+	})
+}
+
+func runReconcile(cmd *cobra.Command, args []string) error {
+	prune, _ := cmd.Flags().GetBool("prune")
+	removeData, _ := cmd.Flags().GetBool("remove-orphaned-data")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	manager := db.NewManager()
+	issues, err := manager.Doctor()
+	if err != nil {
+		return err
+	}
+
+	if len(issues) == 0 {
+		fmt.Println("✅ Registry, containers and data directories are in sync.")
+		return nil
+	}
+
+	fixed, skipped, keptData := 0, 0, 0
+	for _, issue := range issues {
+		printIssue(issue)
+
+		if issue.Fix == db.FixRemoveData && !removeData {
+			keptData++
+		}
+
+		if issue.Fix == "" || (issue.Fix == db.FixPrune && !prune) || (issue.Fix == db.FixRemoveData && !removeData) {
+			skipped++
+			continue
+		}
+
+		if !yes || issue.Fix == db.FixRemoveData {
+			fmt.Printf("   %s? [y/N]: ", issue.Fix)
+			var answer string
+			fmt.Scanln(&answer)
+			if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+				skipped++
+				continue
+			}
+		}
+
+		if err := manager.Reconcile(issue); err != nil {
+			fmt.Printf("❌ %s: %v\n", issue.Database, err)
+			skipped++
+			continue
+		}
+		fixed++
+	}
+
+	fmt.Printf("\n✅ %d fixed, %d left.", fixed, skipped)
+	if !prune {
+		fmt.Print(" Use --prune to remove what cannot be recovered.")
+	}
+	if keptData > 0 {
+		fmt.Print(" Data directories are only removed with --remove-orphaned-data.")
+	}
+	fmt.Println()
+	return nil
+}
+
+func printIssue(issue db.Issue) {
+	subject := issue.Database
+	if subject == "" {
+		subject = issue.ContainerID
+	}
+	if issue.Type != "" {
+		subject = fmt.Sprintf("%s (%s)", subject, issue.Type)
+	}
+
+	fmt.Printf("⚠️  %s: %s: %s\n", subject, issue.Kind, issue.Detail)
+	if issue.Path != "" {
+		fmt.Printf("   Path: %s\n", issue.Path)
+	}
+	if issue.Fix != "" {
+		fmt.Printf("   Fix: %s\n", issue.Fix)
+	}
+}
@@ -152,6 +152,13 @@ func createEnvironment(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// environmentView is an environment as printed by env list, marking the one
+// selected with env switch.
+type environmentView struct {
+	environment.Environment `yaml:",inline"`
+	Current                 bool `json:"current" yaml:"current"`
+}
+
 func listEnvironments(cmd *cobra.Command, args []string) error {
 	manager := environment.NewEnvironmentManager()
 
@@ -160,38 +167,45 @@ func listEnvironments(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list environments: %w", err)
 	}
 
-	if len(environments) == 0 {
-		fmt.Println("No environments found. Create one with 'spindb env create <name>'")
-		return nil
-	}
-
 	current := manager.GetCurrentEnvironment()
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NAME\tDESCRIPTION\tDATABASES\tACTIVE\tCREATED")
-	fmt.Fprintln(w, "----\t-----------\t---------\t------\t-------")
-
+	views := []environmentView{}
 	for _, env := range environments {
-		active := ""
-		if env.Name == current {
-			active = "* CURRENT"
-		} else if env.Active {
-			active = "ACTIVE"
+		views = append(views, environmentView{Environment: *env, Current: env.Name == current})
+	}
+
+	return render(views, func() error {
+		if len(views) == 0 {
+			fmt.Println("No environments found. Create one with 'spindb env create <name>'")
+			return nil
 		}
 
-		created := env.CreatedAt.Format("2006-01-02")
-		dbCount := fmt.Sprintf("%d", len(env.Databases))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDESCRIPTION\tDATABASES\tACTIVE\tCREATED")
+		fmt.Fprintln(w, "----\t-----------\t---------\t------\t-------")
+
+		for _, env := range views {
+			active := ""
+			if env.Current {
+				active = "* CURRENT"
+			} else if env.Active {
+				active = "ACTIVE"
+			}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			env.Name,
-			env.Description,
-			dbCount,
-			active,
-			created,
-		)
-	}
+			created := env.CreatedAt.Format("2006-01-02")
+			dbCount := fmt.Sprintf("%d", len(env.Databases))
 
-	return w.Flush()
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				env.Name,
+				env.Description,
+				dbCount,
+				active,
+				created,
+			)
+		}
+
+		return w.Flush()
+	})
 }
 
 func switchEnvironment(cmd *cobra.Command, args []string) error {
@@ -229,36 +243,38 @@ func showEnvironment(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load environment: %w", err)
 	}
 
-	fmt.Printf("Environment: %s\n", env.Name)
-	fmt.Printf("Description: %s\n", env.Description)
-	fmt.Printf("Active: %t\n", env.Active)
-	fmt.Printf("Created: %s\n", env.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("Updated: %s\n", env.UpdatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("Databases: %d\n", len(env.Databases))
-
-	if len(env.Databases) > 0 {
-		fmt.Printf("\nDatabases in this environment:\n")
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "NAME\tTYPE\tVERSION\tPORT")
-		fmt.Fprintln(w, "----\t----\t-------\t----")
-
-		for _, db := range env.Databases {
-			port := fmt.Sprintf("%d", db.Port)
-			if db.Port == 0 {
-				port = "-"
+	return render(env, func() error {
+		fmt.Printf("Environment: %s\n", env.Name)
+		fmt.Printf("Description: %s\n", env.Description)
+		fmt.Printf("Active: %t\n", env.Active)
+		fmt.Printf("Created: %s\n", env.CreatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Updated: %s\n", env.UpdatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Databases: %d\n", len(env.Databases))
+
+		if len(env.Databases) > 0 {
+			fmt.Printf("\nDatabases in this environment:\n")
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+			fmt.Fprintln(w, "NAME\tTYPE\tVERSION\tPORT")
+			fmt.Fprintln(w, "----\t----\t-------\t----")
+
+			for _, db := range env.Databases {
+				port := fmt.Sprintf("%d", db.Port)
+				if db.Port == 0 {
+					port = "-"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					db.Name,
+					db.Type,
+					db.Version,
+					port,
+				)
 			}
-
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-				db.Name,
-				db.Type,
-				db.Version,
-				port,
-			)
+			return w.Flush()
 		}
-		w.Flush()
-	}
 
-	return nil
+		return nil
+	})
 }
 
 func addDatabaseToEnvironment(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/environment"
+	"github.com/awade12/spindb/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var envVarsCmd = &cobra.Command{
+	Use:   "env-vars [database-name|environment-name]",
+	Short: "Export connection settings as environment variables",
+	Long: `Print DATABASE_URL and the engine's conventional variables (PGHOST, MYSQL_HOST, REDIS_URL, ...)
+for one database, or for every database in a SpinDB environment with each name prefixed
+by the database name (e.g. ORDERS_DATABASE_URL). A database wins over an environment of the same name.`,
+	Example: `  eval $(spindb env-vars myenv --export-format shell)
+  spindb env-vars myapp-db --file .env`,
+	Args: cobra.ExactArgs(1),
+	RunE: exportEnvVars,
+}
+
+func init() {
+	rootCmd.AddCommand(envVarsCmd)
+	envVarsCmd.Flags().String("export-format", "shell", "Variable format (dotenv, shell, json); dotenv when --file is set")
+	envVarsCmd.Flags().String("prefix", "", "Prefix added to every variable name")
+	envVarsCmd.Flags().String("host", "localhost", "Host to use in the variables")
+	envVarsCmd.Flags().String("file", "", "Write to this file (mode 0600) instead of stdout")
+}
+
+func exportEnvVars(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("export-format")
+	prefix, _ := cmd.Flags().GetString("prefix")
+	host, _ := cmd.Flags().GetString("host")
+	file, _ := cmd.Flags().GetString("file")
+
+	if templateFormat != "" {
+		return fmt.Errorf("--format takes a Go template and does not apply to env-vars, use --export-format dotenv, shell or json")
+	}
+
+	if file != "" && !cmd.Flags().Changed("export-format") {
+		format = "dotenv"
+	}
+
+	if format != "dotenv" && format != "shell" && format != "json" {
+		return fmt.Errorf("unsupported export format '%s', use dotenv, shell or json", format)
+	}
+
+	vars, err := collectEnvVars(args[0], host, prefix)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "json":
+		values := make(map[string]string, len(vars))
+		for _, v := range vars {
+			values[v.Name] = v.Value
+		}
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal variables: %w", err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	case "dotenv":
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "%s=%s\n", v.Name, dotenvQuote(v.Value))
+		}
+	default:
+		for _, v := range vars {
+			fmt.Fprintf(&buf, "export %s=%s\n", v.Name, shellQuote(v.Value))
+		}
+	}
+
+	if file != "" {
+		if err := utils.WriteFileAtomic(file, buf.Bytes(), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Wrote %d variables to %s\n", len(vars), file)
+		return nil
+	}
+
+	_, err = os.Stdout.Write(buf.Bytes())
+	return err
+}
+
+func collectEnvVars(name, host, prefix string) ([]db.EnvVar, error) {
+	manager := db.NewManager()
+
+	vars, err := manager.EnvVars(name, host, prefix)
+	if err == nil {
+		return vars, nil
+	}
+
+	envManager := environment.NewEnvironmentManager()
+	if !envManager.EnvironmentExists(name) {
+		return nil, fmt.Errorf("no database or environment named '%s'", name)
+	}
+
+	env, err := envManager.LoadEnvironment(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var dbNames []string
+	for dbName := range env.Databases {
+		dbNames = append(dbNames, dbName)
+	}
+	sort.Strings(dbNames)
+
+	vars = nil
+	for _, dbName := range dbNames {
+		dbVars, err := manager.EnvVars(dbName, host, prefix+db.EnvVarPrefix(dbName))
+		if err != nil {
+			return nil, fmt.Errorf("environment '%s': %w", name, err)
+		}
+		vars = append(vars, dbVars...)
+	}
+
+	return vars, nil
+}
+
+var (
+	plainShellValue  = regexp.MustCompile(`^[A-Za-z0-9_./:@%+=,-]*$`)
+	plainDotenvValue = regexp.MustCompile(`^[A-Za-z0-9_./:@%+=,?&-]*$`)
+)
+
+func shellQuote(value string) string {
+	if value != "" && plainShellValue.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func dotenvQuote(value string) string {
+	if plainDotenvValue.MatchString(value) {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`).Replace(value) + `"`
+}
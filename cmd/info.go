@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/awade12/spindb/internal/db"
 	"github.com/spf13/cobra"
 )
@@ -24,5 +27,61 @@ func showDatabaseInfo(cmd *cobra.Command, args []string) error {
 	showCreds, _ := cmd.Flags().GetBool("show-credentials")
 
 	manager := db.NewManager()
-	return manager.ShowInfo(name, showCreds)
+	info, err := manager.Info(name, showCreds)
+	if err != nil {
+		return err
+	}
+
+	return render(info, func() error {
+		fmt.Printf("Database Information: %s\n", info.Name)
+		fmt.Printf("─────────────────────────────────────\n")
+		fmt.Printf("Type:         %s\n", info.Type)
+		fmt.Printf("Version:      %s\n", info.Version)
+		fmt.Printf("Status:       %s\n", db.StatusLabel(info.Status))
+		fmt.Printf("Created:      %s\n", info.Created.Format("2006-01-02 15:04:05"))
+
+		if info.LastUsed != nil {
+			fmt.Printf("Last Used:    %s\n", info.LastUsed.Format("2006-01-02 15:04:05"))
+		}
+
+		if info.Port > 0 {
+			fmt.Printf("Port:         %d\n", info.Port)
+			if len(info.Ports) > 0 {
+				fmt.Printf("Ports:        %s\n", db.FormatNamedPorts(info.Ports))
+			}
+			if info.Containerized {
+				if info.Public {
+					fmt.Printf("Access:       Public (externally accessible)\n")
+				} else {
+					fmt.Printf("Access:       Private (localhost only)\n")
+				}
+			}
+		}
+
+		if len(info.Extensions) > 0 {
+			fmt.Printf("Extensions:   %s\n", strings.Join(info.Extensions, ", "))
+		}
+
+		if info.Parent != "" {
+			fmt.Printf("Branch Of:    %s\n", info.Parent)
+			fmt.Printf("Database:     %s\n", info.Database)
+		}
+
+		if info.FilePath != "" {
+			fmt.Printf("File Path:    %s\n", info.FilePath)
+		}
+
+		if showCreds && info.Containerized {
+			if info.User != "" {
+				fmt.Printf("User:         %s\n", info.User)
+			}
+			fmt.Printf("Password:     %s\n", info.Password)
+		}
+
+		if info.ContainerID != "" {
+			fmt.Printf("Container ID: %s\n", info.ContainerID)
+		}
+
+		return nil
+	})
 }
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/awade12/spindb/internal/db"
 	"github.com/spf13/cobra"
 )
@@ -14,12 +17,49 @@ var listCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(listCmd)
-	listCmd.Flags().StringP("type", "t", "", "Filter by database type (postgres, mysql, sqlite)")
+	listCmd.Flags().StringP("type", "t", "", "Filter by database type ("+strings.Join(db.EngineNames(), ", ")+")")
+	listCmd.Flags().Bool("show-credentials", false, "Include passwords in json, yaml and --format output")
 }
 
 func listDatabases(cmd *cobra.Command, args []string) error {
 	dbType, _ := cmd.Flags().GetString("type")
+	showCreds, _ := cmd.Flags().GetBool("show-credentials")
 
 	manager := db.NewManager()
-	return manager.ListDatabases(dbType)
+	databases, err := manager.ListDatabases(dbType, showCreds)
+	if err != nil {
+		return err
+	}
+
+	return render(databases, func() error {
+		if len(databases) == 0 {
+			fmt.Println("No databases found.")
+			return nil
+		}
+
+		fmt.Printf("SpinDB Databases:\n\n")
+		for _, info := range databases {
+			fmt.Printf("📊 %s (%s)\n", info.Name, info.Type)
+			fmt.Printf("   Status: %s\n", db.StatusLabel(info.Status))
+			if info.Port > 0 {
+				fmt.Printf("   Port: %d\n", info.Port)
+				if len(info.Ports) > 0 {
+					fmt.Printf("   Ports: %s\n", db.FormatNamedPorts(info.Ports))
+				}
+				if info.Containerized {
+					if info.Public {
+						fmt.Printf("   Access: Public\n")
+					} else {
+						fmt.Printf("   Access: Private\n")
+					}
+				}
+			}
+			if info.FilePath != "" {
+				fmt.Printf("   File: %s\n", info.FilePath)
+			}
+			fmt.Printf("   Created: %s\n", info.Created.Format("2006-01-02 15:04:05"))
+			fmt.Println()
+		}
+		return nil
+	})
 }
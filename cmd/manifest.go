@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awade12/spindb/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+var upCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Create or start the databases declared in spindb.yaml",
+	Long:  `Create every database in the project manifest that does not exist yet, start the stopped ones, and add them to the manifest's environment`,
+	RunE:  manifestUp,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show drift between spindb.yaml and the managed databases",
+	Long:  `Compare the project manifest with the registry and containers and list what 'spindb up' would change`,
+	RunE:  manifestDiff,
+}
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Delete the databases declared in spindb.yaml",
+	Long:  `Stop and delete every database in the project manifest and remove its environment once empty`,
+	RunE:  manifestDown,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{upCmd, diffCmd, downCmd} {
+		rootCmd.AddCommand(cmd)
+		cmd.Flags().StringP("file", "f", manifest.DefaultFile, "Project manifest")
+	}
+
+	downCmd.Flags().Bool("volumes", false, "Also delete data directories and SQLite files")
+	downCmd.Flags().Bool("force", false, "Skip the confirmation prompt")
+}
+
+func loadManifest(cmd *cobra.Command) (*manifest.Manifest, error) {
+	path, _ := cmd.Flags().GetString("file")
+	return manifest.Load(path)
+}
+
+func manifestUp(cmd *cobra.Command, args []string) error {
+	project, err := loadManifest(cmd)
+	if err != nil {
+		return err
+	}
+
+	return manifest.NewManager().Up(project)
+}
+
+func manifestDiff(cmd *cobra.Command, args []string) error {
+	project, err := loadManifest(cmd)
+	if err != nil {
+		return err
+	}
+
+	changes, err := manifest.NewManager().Diff(project)
+	if err != nil {
+		return err
+	}
+
+	return render(changes, func() error {
+		for _, change := range changes {
+			symbol := map[string]string{
+				manifest.ActionCreate: "+",
+				manifest.ActionStart:  "~",
+				manifest.ActionJoin:   "+",
+				manifest.ActionDrift:  "!",
+				manifest.ActionExtra:  "-",
+				manifest.ActionNone:   "=",
+			}[change.Action]
+
+			fmt.Printf("%s %s (%s): %s: %s\n", symbol, change.Database, change.Engine, change.Action, change.Detail)
+		}
+		return nil
+	})
+}
+
+func manifestDown(cmd *cobra.Command, args []string) error {
+	volumes, _ := cmd.Flags().GetBool("volumes")
+	force, _ := cmd.Flags().GetBool("force")
+
+	project, err := loadManifest(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		what := "databases"
+		if volumes {
+			what = "databases and their data"
+		}
+		fmt.Printf("Are you sure you want to delete the %s declared in %s? This action cannot be undone.\n", what, project.Path())
+		fmt.Print("Type 'yes' to confirm: ")
+		var confirmation string
+		fmt.Scanln(&confirmation)
+		if strings.ToLower(confirmation) != "yes" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	return manifest.NewManager().Down(project, volumes)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/awade12/spindb/internal/backup"
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var migrateStateCmd = &cobra.Command{
+	Use:   "migrate-state",
+	Short: "Upgrade stored state to the current schema",
+	Long: `Upgrade the database registry, environment files and templates to the
+current schema version. Each changed file is backed up as <file>.v<old>.bak,
+with its passwords blanked since they are in the secrets store by then.
+Backup files without a manifest get one, inferred from their name and contents.
+
+Other commands upgrade the state files automatically, but backup manifests are
+only written here. Use --dry-run to see what would change.`,
+	Args: cobra.NoArgs,
+	// Overrides the automatic migration on the root command, so --dry-run
+	// still has something to report.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return config.Check() },
+	RunE:              migrateState,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateStateCmd)
+	migrateStateCmd.Flags().Bool("dry-run", false, "Show what would change without writing anything")
+}
+
+func migrateState(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	results, err := config.MigrateState(utils.SpinDBHome(), dryRun)
+	for _, result := range results {
+		fmt.Printf("%s: v%d -> v%d\n", result.Path, result.From, result.To)
+		for _, step := range result.Steps {
+			fmt.Printf("   - %s\n", step)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	migrated, err := backup.MigrateBackups(config.Load().Storage.BackupDir, dryRun)
+	for _, path := range migrated {
+		fmt.Printf("%s: add backup manifest\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(results) == 0 && len(migrated) == 0:
+		fmt.Printf("✅ State is up to date (schema version %d)\n", config.SchemaVersion)
+	case dryRun:
+		fmt.Printf("%d file(s) would be migrated. Run without --dry-run to apply.\n", len(results)+len(migrated))
+	default:
+		if len(results) > 0 {
+			fmt.Printf("✅ Migrated %d file(s) to schema version %d\n", len(results), config.SchemaVersion)
+		}
+		if len(migrated) > 0 {
+			fmt.Printf("✅ Wrote manifests for %d backup(s)\n", len(migrated))
+		}
+	}
+
+	return nil
+}
+
+// autoMigrateState only warns when a migration fails: commands that just
+// read still work, and the stores refuse to write an outdated file. Backup
+// manifests are left to migrate-state, since a backup may still be written
+// by another process.
+func autoMigrateState(cmd *cobra.Command, args []string) error {
+	results, err := config.MigrateState(utils.SpinDBHome(), false)
+	for _, result := range results {
+		fmt.Fprintf(os.Stderr, "Upgraded %s from schema v%d to v%d (backup: %s.v%d.bak)\n",
+			result.Path, result.From, result.To, result.Path, result.From)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to migrate state: %v (run 'spindb migrate-state' for details)\n", err)
+	}
+	return nil
+}
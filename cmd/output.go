@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var outputFormat string
+var templateFormat string
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "output format for read commands (table, json, yaml)")
+	rootCmd.PersistentFlags().StringVar(&templateFormat, "format", "", "Go template applied to each result, e.g. '{{.Name}} {{.Status}}'")
+}
+
+// validateOutput rejects an unknown --output before a command does any work.
+func validateOutput(cmd *cobra.Command) error {
+	switch outputFormat {
+	case "table", "json", "yaml":
+	default:
+		return fmt.Errorf("unsupported output format '%s', use table, json or yaml", outputFormat)
+	}
+
+	if templateFormat != "" && cmd.Flags().Changed("output") {
+		return fmt.Errorf("--format and --output cannot be used together")
+	}
+
+	return nil
+}
+
+// render writes the result of a read command in the requested format. table
+// draws the human-readable view and is only called for --output table.
+func render(data any, table func() error) error {
+	if templateFormat != "" {
+		return renderTemplate(data)
+	}
+
+	// Empty lists come back as nil slices; print them as [] rather than null.
+	if value := reflect.ValueOf(data); value.Kind() == reflect.Slice && value.IsNil() {
+		data = []any{}
+	}
+
+	switch outputFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return table()
+	}
+}
+
+// renderTemplate executes --format once per element for lists, like
+// docker ps --format, and once for single results.
+func renderTemplate(data any) error {
+	tmpl, err := template.New("format").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(templateFormat)
+	if err != nil {
+		return fmt.Errorf("failed to parse --format template: %w", err)
+	}
+
+	items := []any{data}
+	if value := reflect.ValueOf(data); value.Kind() == reflect.Slice {
+		items = items[:0]
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, value.Index(i).Interface())
+		}
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(os.Stdout, item); err != nil {
+			return fmt.Errorf("failed to execute --format template: %w", err)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/awade12/spindb/internal/backup"
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/environment"
+	"github.com/spf13/cobra"
+)
+
+var backupPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete backups the retention policies do not keep",
+	Long:  `Apply each database's retention policy to its backups and delete the ones it does not keep. The newest backup of a database is never pruned.`,
+	Args:  cobra.NoArgs,
+	RunE:  pruneBackups,
+}
+
+var backupRetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Manage backup retention policies",
+	Long: `Manage backup retention policies.
+
+A policy can be set on a database, on an environment for all its databases, or
+globally in the config (backup.retention.*). The most specific one applies.`,
+}
+
+var backupRetentionShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the retention policy in effect for each database",
+	Long:  `Show the retention policy in effect for each database and where it is set`,
+	Args:  cobra.NoArgs,
+	RunE:  showRetention,
+}
+
+var backupRetentionSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a retention policy",
+	Long:  `Set the retention policy of a database (--database), an environment (--env) or, by default, the global config`,
+	Args:  cobra.NoArgs,
+	RunE:  setRetention,
+}
+
+var backupRetentionClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove a retention policy",
+	Long:  `Remove the retention policy of a database (--database), an environment (--env) or, by default, the global config`,
+	Args:  cobra.NoArgs,
+	RunE:  clearRetention,
+}
+
+func init() {
+	backupCmd.AddCommand(backupPruneCmd)
+	backupCmd.AddCommand(backupRetentionCmd)
+	backupRetentionCmd.AddCommand(backupRetentionShowCmd)
+	backupRetentionCmd.AddCommand(backupRetentionSetCmd)
+	backupRetentionCmd.AddCommand(backupRetentionClearCmd)
+
+	backupPruneCmd.Flags().String("database", "", "Only prune backups of this database")
+	backupPruneCmd.Flags().Bool("dry-run", false, "Show what would be deleted without deleting anything")
+
+	for _, cmd := range []*cobra.Command{backupRetentionSetCmd, backupRetentionClearCmd} {
+		cmd.Flags().String("database", "", "Database to set the policy on")
+		cmd.Flags().String("env", "", "Environment to set the policy on")
+		cmd.Flags().Bool("project", false, "Write the global policy to .spindb.yaml in the current directory")
+		cmd.MarkFlagsMutuallyExclusive("database", "env", "project")
+	}
+
+	backupRetentionSetCmd.Flags().Int("keep-last", 0, "Keep the N newest backups")
+	backupRetentionSetCmd.Flags().Int("keep-daily", 0, "Keep the newest backup of each of the last N days with backups")
+	backupRetentionSetCmd.Flags().Int("keep-weekly", 0, "Keep the newest backup of each of the last N weeks with backups")
+	backupRetentionSetCmd.Flags().Int("keep-monthly", 0, "Keep the newest backup of each of the last N months with backups")
+	backupRetentionSetCmd.Flags().String("max-size", "", "Prune the oldest backups until the database's backups fit, e.g. 10GB")
+}
+
+func pruneBackups(cmd *cobra.Command, args []string) error {
+	dbName, _ := cmd.Flags().GetString("database")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	manager := backup.NewBackupManager()
+	pruned, err := manager.Prune(dbName, dryRun)
+	if err != nil {
+		return err
+	}
+
+	return render(pruned, func() error {
+		if len(pruned) == 0 {
+			fmt.Println("✅ Nothing to prune.")
+			return nil
+		}
+
+		var size int64
+		for _, backup := range pruned {
+			size += backup.Size
+			fmt.Printf("   %s (%s, %.2f MB)\n", backup.Name, backup.CreatedAt.Format("2006-01-02 15:04"), float64(backup.Size)/(1024*1024))
+		}
+
+		if dryRun {
+			fmt.Printf("%d backup(s) would be deleted, freeing %.2f MB. Run without --dry-run to apply.\n", len(pruned), float64(size)/(1024*1024))
+		} else {
+			fmt.Printf("✅ Deleted %d backup(s), freeing %.2f MB.\n", len(pruned), float64(size)/(1024*1024))
+		}
+		return nil
+	})
+}
+
+func showRetention(cmd *cobra.Command, args []string) error {
+	manager := backup.NewBackupManager()
+	policies, err := manager.Policies()
+	if err != nil {
+		return err
+	}
+
+	return render(policies, func() error {
+		if len(policies) == 0 {
+			fmt.Println("No databases or backups found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "DATABASE\tPOLICY\tSOURCE")
+		fmt.Fprintln(w, "--------\t------\t------")
+
+		for _, info := range policies {
+			source := info.Source
+			if info.Environment != "" {
+				source = fmt.Sprintf("%s '%s'", info.Source, info.Environment)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", info.Database, info.Policy, source)
+		}
+
+		return w.Flush()
+	})
+}
+
+func setRetention(cmd *cobra.Command, args []string) error {
+	policy := &config.RetentionPolicy{}
+	policy.KeepLast, _ = cmd.Flags().GetInt("keep-last")
+	policy.KeepDaily, _ = cmd.Flags().GetInt("keep-daily")
+	policy.KeepWeekly, _ = cmd.Flags().GetInt("keep-weekly")
+	policy.KeepMonthly, _ = cmd.Flags().GetInt("keep-monthly")
+	policy.MaxSize, _ = cmd.Flags().GetString("max-size")
+
+	if policy.IsZero() {
+		return fmt.Errorf("set at least one of --keep-last, --keep-daily, --keep-weekly, --keep-monthly or --max-size")
+	}
+
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	return applyRetention(cmd, policy)
+}
+
+func clearRetention(cmd *cobra.Command, args []string) error {
+	return applyRetention(cmd, nil)
+}
+
+// applyRetention writes policy to the scope chosen by the flags. A nil
+// policy clears it.
+func applyRetention(cmd *cobra.Command, policy *config.RetentionPolicy) error {
+	dbName, _ := cmd.Flags().GetString("database")
+	envName, _ := cmd.Flags().GetString("env")
+	project, _ := cmd.Flags().GetBool("project")
+
+	var scope string
+	switch {
+	case dbName != "":
+		if err := backup.NewBackupManager().SetDatabaseRetention(dbName, policy); err != nil {
+			return err
+		}
+		scope = fmt.Sprintf("database '%s'", dbName)
+	case envName != "":
+		if err := environment.NewEnvironmentManager().SetRetention(envName, policy); err != nil {
+			return err
+		}
+		scope = fmt.Sprintf("environment '%s'", envName)
+	default:
+		path := configFilePath()
+		if project {
+			path = config.ProjectConfigPath()
+		}
+
+		global := policy
+		if global == nil {
+			global = &config.RetentionPolicy{}
+		}
+
+		for key, value := range map[string]string{
+			"backup.retention.keep_last":    strconv.Itoa(global.KeepLast),
+			"backup.retention.keep_daily":   strconv.Itoa(global.KeepDaily),
+			"backup.retention.keep_weekly":  strconv.Itoa(global.KeepWeekly),
+			"backup.retention.keep_monthly": strconv.Itoa(global.KeepMonthly),
+			"backup.retention.max_size":     global.MaxSize,
+		} {
+			if err := config.SetInFile(path, key, value); err != nil {
+				return err
+			}
+		}
+		scope = fmt.Sprintf("global config (%s)", path)
+	}
+
+	if policy == nil {
+		fmt.Printf("✅ Retention policy cleared for %s\n", scope)
+	} else {
+		fmt.Printf("✅ Retention policy for %s: %s\n", scope, policy)
+	}
+	return nil
+}
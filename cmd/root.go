@@ -1,20 +1,23 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/awade12/spindb/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
+var homeDir string
+var loadedConfigFiles []string
 
 var rootCmd = &cobra.Command{
 	Use:   "spindb",
 	Short: "A powerful CLI tool to spin up and manage databases",
 	Long: `SpinDB is a CLI tool for spinning up and managing databases 
 (PostgreSQL, MySQL, SQLite) from the terminal with no web UI required.`,
+	PersistentPreRunE: preRun,
 }
 
 func Execute() {
@@ -26,26 +29,33 @@ func Execute() {
 
 func init() {
 	cobra.OnInitialize(initConfig)
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.spindb/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $SPINDB_HOME/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&homeDir, "home", "", "SpinDB state directory (default is $SPINDB_HOME or $HOME/.spindb)")
+	rootCmd.PersistentFlags().String("data-dir", "", "directory for database data (overrides storage.data_dir)")
+	rootCmd.PersistentFlags().String("backup-dir", "", "directory for backups (overrides storage.backup_dir)")
+
+	viper.BindPFlag("storage.data_dir", rootCmd.PersistentFlags().Lookup("data-dir"))
+	viper.BindPFlag("storage.backup_dir", rootCmd.PersistentFlags().Lookup("backup-dir"))
 }
 
-func initConfig() {
-	if cfgFile != "" {
-		viper.SetConfigFile(cfgFile)
-	} else {
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		viper.AddConfigPath(home + "/.spindb")
-		viper.AddConfigPath(".")
-		viper.SetConfigType("yaml")
-		viper.SetConfigName("config")
+func preRun(cmd *cobra.Command, args []string) error {
+	if err := validateOutput(cmd); err != nil {
+		return err
 	}
+	if err := config.Check(); err != nil {
+		return err
+	}
+	return autoMigrateState(cmd, args)
+}
 
-	viper.SetEnvPrefix("SPINDB")
-	viper.AutomaticEnv()
-
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+func initConfig() {
+	// Exported rather than kept in a variable so every store, and any
+	// spindb process started from this one, resolves the same home.
+	if homeDir != "" {
+		cobra.CheckErr(os.Setenv("SPINDB_HOME", homeDir))
 	}
+
+	files, err := config.Init(cfgFile)
+	cobra.CheckErr(err)
+	loadedConfigFiles = files
 }
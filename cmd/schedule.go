@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/awade12/spindb/internal/agent"
+	"github.com/awade12/spindb/internal/backup"
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/environment"
+	"github.com/spf13/cobra"
+)
+
+var backupScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage backup schedules",
+	Long: `Manage the backup schedules run by 'spindb agent'.
+
+A schedule can be set on a database, or on an environment for all its databases.
+The database's own schedule takes precedence.`,
+}
+
+var backupScheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the backup schedule of each database",
+	Long:  `List the databases with a backup schedule and where it is set`,
+	Args:  cobra.NoArgs,
+	RunE:  listSchedules,
+}
+
+var backupScheduleSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a backup schedule",
+	Long: `Set the backup schedule of a database (--database) or an environment (--env).
+
+--cron takes a five-field cron expression (minute hour day month weekday) in local time,
+or a macro such as @hourly, @daily or @weekly.`,
+	Example: `  spindb backup schedule set --database mydb --cron "0 3 * * *"
+  spindb backup schedule set --env staging --cron @hourly --compress`,
+	Args: cobra.NoArgs,
+	RunE: setSchedule,
+}
+
+var backupScheduleClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove a backup schedule",
+	Long:  `Remove the backup schedule of a database (--database) or an environment (--env)`,
+	Args:  cobra.NoArgs,
+	RunE:  clearSchedule,
+}
+
+var backupStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the backup agent and scheduled backups",
+	Long:  `Show whether 'spindb agent' is running and, for each scheduled database, its last and next run`,
+	Args:  cobra.NoArgs,
+	RunE:  showBackupStatus,
+}
+
+func init() {
+	backupCmd.AddCommand(backupScheduleCmd)
+	backupCmd.AddCommand(backupStatusCmd)
+	backupScheduleCmd.AddCommand(backupScheduleListCmd)
+	backupScheduleCmd.AddCommand(backupScheduleSetCmd)
+	backupScheduleCmd.AddCommand(backupScheduleClearCmd)
+
+	for _, cmd := range []*cobra.Command{backupScheduleSetCmd, backupScheduleClearCmd} {
+		cmd.Flags().String("database", "", "Database to schedule")
+		cmd.Flags().String("env", "", "Environment to schedule")
+		cmd.MarkFlagsMutuallyExclusive("database", "env")
+		cmd.MarkFlagsOneRequired("database", "env")
+	}
+
+	backupScheduleSetCmd.Flags().String("cron", "", "When to back up, as a cron expression or macro")
+	backupScheduleSetCmd.Flags().Bool("compress", false, "Compress the scheduled backups")
+	backupScheduleSetCmd.MarkFlagRequired("cron")
+}
+
+func listSchedules(cmd *cobra.Command, args []string) error {
+	schedules, err := backup.NewBackupManager().Schedules()
+	if err != nil {
+		return err
+	}
+
+	return render(schedules, func() error {
+		if len(schedules) == 0 {
+			fmt.Println("No backup schedules configured.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "DATABASE\tCRON\tCOMPRESS\tSOURCE")
+		fmt.Fprintln(w, "--------\t----\t--------\t------")
+
+		for _, info := range schedules {
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", info.Database, info.Schedule.Cron, info.Schedule.Compress, scheduleSource(info))
+		}
+
+		return w.Flush()
+	})
+}
+
+func setSchedule(cmd *cobra.Command, args []string) error {
+	schedule := &config.BackupSchedule{}
+	schedule.Cron, _ = cmd.Flags().GetString("cron")
+	schedule.Compress, _ = cmd.Flags().GetBool("compress")
+
+	cron, err := agent.ParseCron(schedule.Cron)
+	if err != nil {
+		return err
+	}
+
+	if err := applySchedule(cmd, schedule); err != nil {
+		return err
+	}
+
+	if next := cron.Next(time.Now()); !next.IsZero() {
+		fmt.Printf("   Next run: %s\n", next.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func clearSchedule(cmd *cobra.Command, args []string) error {
+	return applySchedule(cmd, nil)
+}
+
+// applySchedule writes schedule to the scope chosen by the flags. A nil
+// schedule clears it.
+func applySchedule(cmd *cobra.Command, schedule *config.BackupSchedule) error {
+	dbName, _ := cmd.Flags().GetString("database")
+	envName, _ := cmd.Flags().GetString("env")
+
+	var scope string
+	if dbName != "" {
+		if err := backup.NewBackupManager().SetDatabaseSchedule(dbName, schedule); err != nil {
+			return err
+		}
+		scope = fmt.Sprintf("database '%s'", dbName)
+	} else {
+		if err := environment.NewEnvironmentManager().SetSchedule(envName, schedule); err != nil {
+			return err
+		}
+		scope = fmt.Sprintf("environment '%s'", envName)
+	}
+
+	if schedule == nil {
+		fmt.Printf("✅ Backup schedule cleared for %s\n", scope)
+	} else {
+		fmt.Printf("✅ Backup schedule for %s: %s\n", scope, schedule.Cron)
+	}
+	return nil
+}
+
+func showBackupStatus(cmd *cobra.Command, args []string) error {
+	report, err := agent.BackupStatus()
+	if err != nil {
+		return err
+	}
+
+	return render(report, func() error {
+		if report.Running {
+			fmt.Printf("Agent: running (pid %d, last heartbeat %s)\n", report.PID, report.Heartbeat.Format("2006-01-02 15:04:05"))
+		} else {
+			fmt.Println("Agent: not running (start it with 'spindb agent')")
+		}
+		fmt.Println()
+
+		if len(report.Databases) == 0 {
+			fmt.Println("No backup schedules configured.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "DATABASE\tCRON\tLAST RUN\tRESULT\tNEXT RUN")
+		fmt.Fprintln(w, "--------\t----\t--------\t------\t--------")
+
+		for _, entry := range report.Databases {
+			lastRun, result := "never", "-"
+			if run := entry.LastRun; run != nil {
+				lastRun = run.StartedAt.Format("2006-01-02 15:04")
+				if run.Success {
+					result = "✅ " + run.Backup
+				} else {
+					result = "❌ " + run.Error
+				}
+			}
+
+			nextRun := "-"
+			if entry.NextRun != nil {
+				nextRun = entry.NextRun.Format("2006-01-02 15:04")
+			}
+			if entry.Error != "" {
+				nextRun = "❌ " + entry.Error
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Database, entry.Schedule.Cron, lastRun, result, nextRun)
+		}
+
+		return w.Flush()
+	})
+}
+
+func scheduleSource(info backup.ScheduleInfo) string {
+	if info.Environment != "" {
+		return fmt.Sprintf("%s '%s'", info.Source, info.Environment)
+	}
+	return info.Source
+}
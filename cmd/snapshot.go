@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/awade12/spindb/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Snapshot and roll back database data directories",
+	Long:  `Create, list, restore and delete on-disk snapshots of a Docker database's data directory`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create [database-name]",
+	Short: "Create a snapshot of a database",
+	Long:  `Stop the database, copy its data directory into a named snapshot and start it again`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  createSnapshot,
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list [database-name]",
+	Short: "List snapshots",
+	Long:  `List snapshots of the specified database, or of all databases`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  listSnapshots,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore [database-name] [snapshot-name]",
+	Short: "Roll a database back to a snapshot",
+	Long:  `Stop the database, replace its data directory with the snapshot and start it again`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  restoreSnapshot,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete [database-name] [snapshot-name]",
+	Short: "Delete a snapshot",
+	Long:  `Delete the specified snapshot of a database`,
+	Args:  cobra.ExactArgs(2),
+	RunE:  deleteSnapshot,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+
+	snapshotCreateCmd.Flags().StringP("name", "n", "", "Snapshot name (default: timestamp)")
+}
+
+func createSnapshot(cmd *cobra.Command, args []string) error {
+	dbName := args[0]
+	name, _ := cmd.Flags().GetString("name")
+
+	manager := snapshot.NewSnapshotManager()
+
+	fmt.Printf("Creating snapshot of database '%s'...\n", dbName)
+	info, err := manager.CreateSnapshot(dbName, name)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	fmt.Printf("✅ Snapshot created successfully!\n")
+	fmt.Printf("   Name: %s\n", info.Name)
+	fmt.Printf("   Size: %.2f MB\n", float64(info.Size)/(1024*1024))
+	fmt.Printf("   Path: %s\n", info.Path)
+
+	return nil
+}
+
+func listSnapshots(cmd *cobra.Command, args []string) error {
+	dbName := ""
+	if len(args) > 0 {
+		dbName = args[0]
+	}
+
+	manager := snapshot.NewSnapshotManager()
+
+	snapshots, err := manager.ListSnapshots(dbName)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	return render(snapshots, func() error {
+		if len(snapshots) == 0 {
+			fmt.Println("No snapshots found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tDATABASE\tTYPE\tSIZE\tCREATED")
+		fmt.Fprintln(w, "----\t--------\t----\t----\t-------")
+
+		for _, snapshot := range snapshots {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				snapshot.Name,
+				snapshot.Database,
+				snapshot.Type,
+				fmt.Sprintf("%.2f MB", float64(snapshot.Size)/(1024*1024)),
+				snapshot.CreatedAt.Format("2006-01-02 15:04"),
+			)
+		}
+
+		return w.Flush()
+	})
+}
+
+func restoreSnapshot(cmd *cobra.Command, args []string) error {
+	dbName := args[0]
+	snapshotName := args[1]
+
+	manager := snapshot.NewSnapshotManager()
+
+	fmt.Printf("Restoring database '%s' to snapshot '%s'...\n", dbName, snapshotName)
+	if err := manager.RestoreSnapshot(dbName, snapshotName); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	fmt.Printf("✅ Database '%s' restored to snapshot '%s'!\n", dbName, snapshotName)
+	return nil
+}
+
+func deleteSnapshot(cmd *cobra.Command, args []string) error {
+	dbName := args[0]
+	snapshotName := args[1]
+
+	manager := snapshot.NewSnapshotManager()
+
+	if err := manager.DeleteSnapshot(dbName, snapshotName); err != nil {
+		return fmt.Errorf("failed to delete snapshot: %w", err)
+	}
+
+	fmt.Printf("✅ Snapshot '%s' of '%s' deleted successfully!\n", snapshotName, dbName)
+	return nil
+}
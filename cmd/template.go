@@ -85,21 +85,50 @@ func init() {
 
 	templateCreateCmd.Flags().StringP("name", "n", "", "Template name (required)")
 	templateCreateCmd.Flags().StringP("description", "d", "", "Template description")
-	templateCreateCmd.Flags().StringP("type", "t", "", "Database type (postgres/mysql/sqlite) (required)")
+	templateCreateCmd.Flags().StringP("type", "t", "", "Database type ("+strings.Join(db.EngineNames(), "/")+") (required)")
 	templateCreateCmd.Flags().StringP("version", "v", "", "Database version")
 	templateCreateCmd.Flags().StringP("user", "u", "", "Database user")
 	templateCreateCmd.Flags().StringP("password", "p", "", "Database password")
 	templateCreateCmd.Flags().StringP("port", "", "", "Database port")
 	templateCreateCmd.Flags().StringSliceP("tags", "", []string{}, "Template tags")
+	templateCreateCmd.Flags().StringSlice("extensions", []string{}, "Extensions to enable on install (postgres: postgis, timescaledb, vector)")
 	templateCreateCmd.MarkFlagRequired("name")
 	templateCreateCmd.MarkFlagRequired("type")
 
+	templateListCmd.Flags().Bool("show-credentials", false, "Include template passwords in json, yaml and --format output")
+	templateShowCmd.Flags().Bool("show-credentials", false, "Show the template password")
+
 	templateInstallCmd.Flags().StringP("password", "p", "", "Override template password")
 	templateInstallCmd.Flags().StringP("port", "", "", "Override template port")
 	templateInstallCmd.Flags().Bool("public", false, "Make database publicly accessible")
 }
 
+// templateView is a template as printed by the read commands, with its
+// source and the password removed unless credentials were requested.
+type templateView struct {
+	config.Template `yaml:",inline"`
+	Source          string `json:"source" yaml:"source"`
+}
+
+func newTemplateView(template *config.Template, showCredentials bool) templateView {
+	view := templateView{Template: *template, Source: "predefined"}
+	if template.CreatedAt.After(template.CreatedAt.Truncate(24 * 365 * 10)) {
+		view.Source = "custom"
+	}
+
+	view.Config = make(map[string]string, len(template.Config))
+	for key, value := range template.Config {
+		if key == "password" && !showCredentials {
+			continue
+		}
+		view.Config[key] = value
+	}
+
+	return view
+}
+
 func listTemplates(cmd *cobra.Command, args []string) error {
+	showCreds, _ := cmd.Flags().GetBool("show-credentials")
 	store := config.NewTemplateStore()
 
 	predefined := config.GetPredefinedTemplates()
@@ -108,33 +137,35 @@ func listTemplates(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load custom templates: %w", err)
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NAME\tTYPE\tVERSION\tDESCRIPTION\tSOURCE\tTAGS")
-	fmt.Fprintln(w, "----\t----\t-------\t-----------\t------\t----")
-
 	allTemplates := append(predefined, custom...)
 	sort.Slice(allTemplates, func(i, j int) bool {
 		return allTemplates[i].Name < allTemplates[j].Name
 	})
 
+	views := []templateView{}
 	for _, template := range allTemplates {
-		source := "predefined"
-		if template.CreatedAt.After(template.CreatedAt.Truncate(24 * 365 * 10)) {
-			source = "custom"
-		}
-
-		tags := strings.Join(template.Tags, ", ")
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-			template.Name,
-			template.Type,
-			template.Version,
-			template.Description,
-			source,
-			tags,
-		)
+		views = append(views, newTemplateView(template, showCreds))
 	}
 
-	return w.Flush()
+	return render(views, func() error {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTYPE\tVERSION\tDESCRIPTION\tSOURCE\tTAGS")
+		fmt.Fprintln(w, "----\t----\t-------\t-----------\t------\t----")
+
+		for _, view := range views {
+			tags := strings.Join(view.Tags, ", ")
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				view.Name,
+				view.Type,
+				view.Version,
+				view.Description,
+				view.Source,
+				tags,
+			)
+		}
+
+		return w.Flush()
+	})
 }
 
 func createTemplate(cmd *cobra.Command, args []string) error {
@@ -146,9 +177,10 @@ func createTemplate(cmd *cobra.Command, args []string) error {
 	password, _ := cmd.Flags().GetString("password")
 	port, _ := cmd.Flags().GetString("port")
 	tags, _ := cmd.Flags().GetStringSlice("tags")
+	extensions, _ := cmd.Flags().GetStringSlice("extensions")
 
-	if dbType != "postgres" && dbType != "mysql" && dbType != "sqlite" {
-		return fmt.Errorf("invalid database type: %s (must be postgres, mysql, or sqlite)", dbType)
+	if _, err := db.GetEngine(dbType); err != nil {
+		return fmt.Errorf("invalid database type: %s (must be one of: %s)", dbType, strings.Join(db.EngineNames(), ", "))
 	}
 
 	store := config.NewTemplateStore()
@@ -167,6 +199,9 @@ func createTemplate(cmd *cobra.Command, args []string) error {
 	if port != "" {
 		templateConfig["port"] = port
 	}
+	if len(extensions) > 0 {
+		templateConfig["extensions"] = strings.Join(extensions, ",")
+	}
 
 	template := &config.Template{
 		Name:        name,
@@ -203,6 +238,7 @@ func deleteTemplate(cmd *cobra.Command, args []string) error {
 
 func showTemplate(cmd *cobra.Command, args []string) error {
 	name := args[0]
+	showCreds, _ := cmd.Flags().GetBool("show-credentials")
 	store := config.NewTemplateStore()
 
 	template, err := store.Load(name)
@@ -219,31 +255,34 @@ func showTemplate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	fmt.Printf("Template: %s\n", template.Name)
-	fmt.Printf("Type: %s\n", template.Type)
-	fmt.Printf("Version: %s\n", template.Version)
-	fmt.Printf("Description: %s\n", template.Description)
+	view := newTemplateView(template, showCreds)
+	return render(view, func() error {
+		fmt.Printf("Template: %s\n", template.Name)
+		fmt.Printf("Type: %s\n", template.Type)
+		fmt.Printf("Version: %s\n", template.Version)
+		fmt.Printf("Description: %s\n", template.Description)
 
-	if len(template.Tags) > 0 {
-		fmt.Printf("Tags: %s\n", strings.Join(template.Tags, ", "))
-	}
+		if len(template.Tags) > 0 {
+			fmt.Printf("Tags: %s\n", strings.Join(template.Tags, ", "))
+		}
 
-	if !template.CreatedAt.IsZero() {
-		fmt.Printf("Created: %s\n", template.CreatedAt.Format("2006-01-02 15:04:05"))
-	}
+		if !template.CreatedAt.IsZero() {
+			fmt.Printf("Created: %s\n", template.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
 
-	if len(template.Config) > 0 {
-		fmt.Printf("\nConfiguration:\n")
-		for key, value := range template.Config {
-			if key == "password" {
-				fmt.Printf("  %s: ***\n", key)
-			} else {
-				fmt.Printf("  %s: %s\n", key, value)
+		if len(template.Config) > 0 {
+			fmt.Printf("\nConfiguration:\n")
+			for key, value := range template.Config {
+				if key == "password" && !showCreds {
+					fmt.Printf("  %s: ***\n", key)
+				} else {
+					fmt.Printf("  %s: %s\n", key, value)
+				}
 			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
 
 func installTemplate(cmd *cobra.Command, args []string) error {
@@ -273,78 +312,57 @@ func installTemplate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	manager := db.NewManager()
-
-	switch template.Type {
-	case "postgres":
-		port := 0
-		if portOverride != "" {
-			if p, err := strconv.Atoi(portOverride); err == nil {
-				port = p
-			}
-		} else if template.Config["port"] != "" {
-			if p, err := strconv.Atoi(template.Config["port"]); err == nil {
-				port = p
-			}
-		}
+	engine, err := db.GetEngine(template.Type)
+	if err != nil {
+		return err
+	}
 
-		password := template.Config["password"]
-		if passwordOverride != "" {
-			password = passwordOverride
-		}
+	manager := db.NewManager()
 
-		config := &db.PostgresConfig{
-			Name:     databaseName,
-			User:     template.Config["user"],
-			Password: password,
-			Port:     port,
-			Version:  template.Version,
-			Public:   public,
+	if !engine.Containerized() {
+		config := &db.InstanceConfig{
+			FilePath: databaseName + ".db",
 		}
 
-		fmt.Printf("Creating PostgreSQL database '%s' from template '%s'...\n", databaseName, templateName)
-		return manager.CreatePostgres(config)
-
-	case "mysql":
-		port := 0
-		if portOverride != "" {
-			if p, err := strconv.Atoi(portOverride); err == nil {
-				port = p
-			}
-		} else if template.Config["port"] != "" {
-			if p, err := strconv.Atoi(template.Config["port"]); err == nil {
-				port = p
-			}
-		}
+		fmt.Printf("Creating %s database '%s' from template '%s'...\n", engine.DisplayName(), databaseName, templateName)
+		return manager.Create(engine.Name(), config)
+	}
 
-		password := template.Config["password"]
-		if passwordOverride != "" {
-			password = passwordOverride
+	port := 0
+	if portOverride != "" {
+		if p, err := strconv.Atoi(portOverride); err == nil {
+			port = p
 		}
-
-		config := &db.MySQLConfig{
-			Name:     databaseName,
-			User:     template.Config["user"],
-			Password: password,
-			Port:     port,
-			Version:  template.Version,
-			Public:   public,
+	} else if template.Config["port"] != "" {
+		if p, err := strconv.Atoi(template.Config["port"]); err == nil {
+			port = p
 		}
+	}
 
-		fmt.Printf("Creating MySQL database '%s' from template '%s'...\n", databaseName, templateName)
-		return manager.CreateMySQL(config)
+	password := template.Config["password"]
+	if passwordOverride != "" {
+		password = passwordOverride
+	}
 
-	case "sqlite":
-		config := &db.SQLiteConfig{
-			FilePath: databaseName + ".db",
+	var extensions []string
+	if template.Config["extensions"] != "" {
+		for _, extension := range strings.Split(template.Config["extensions"], ",") {
+			extensions = append(extensions, strings.TrimSpace(extension))
 		}
+	}
 
-		fmt.Printf("Creating SQLite database '%s' from template '%s'...\n", databaseName, templateName)
-		return manager.CreateSQLite(config)
-
-	default:
-		return fmt.Errorf("unsupported database type: %s", template.Type)
+	config := &db.InstanceConfig{
+		Name:       databaseName,
+		User:       template.Config["user"],
+		Password:   password,
+		Port:       port,
+		Version:    template.Version,
+		Public:     public,
+		Extensions: extensions,
 	}
+
+	fmt.Printf("Creating %s database '%s' from template '%s'...\n", engine.DisplayName(), databaseName, templateName)
+	return manager.Create(engine.Name(), config)
 }
 
 func importTemplate(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awade12/spindb/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var urlCmd = &cobra.Command{
+	Use:   "url [database-name]",
+	Short: "Print the connection URL of a database",
+	Long:  `Print a connection URL for the database in its engine's scheme (postgres://, mysql://, sqlserver://, mongodb://, redis://, clickhouse://, file:)`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  showDatabaseURL,
+}
+
+func init() {
+	rootCmd.AddCommand(urlCmd)
+	urlCmd.Flags().String("host", "localhost", "Host to put in the URL, e.g. the server address of a public database")
+}
+
+func showDatabaseURL(cmd *cobra.Command, args []string) error {
+	host, _ := cmd.Flags().GetString("host")
+
+	manager := db.NewManager()
+	url, err := manager.URL(args[0], host)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(url)
+	return nil
+}
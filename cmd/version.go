@@ -16,6 +16,9 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
 	Long:  `Display the current version of SpinDB`,
+	// Skip state migration so the version can be checked even when the
+	// state was written by a newer spindb.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Printf("SpinDB %s\n", Version)
 		fmt.Printf("Git commit: %s\n", GitCommit)
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/awade12/spindb/internal/backup"
+	"github.com/awade12/spindb/internal/utils"
+)
+
+// Agent runs the backup schedules of the registered databases. Schedules
+// are re-read every minute, so changes apply without a restart.
+type Agent struct {
+	backups *backup.BackupManager
+	status  *Status
+	logger  *log.Logger
+	// checked is when the schedules were last checked. A schedule is due
+	// when it matched at some minute since then.
+	checked time.Time
+}
+
+func New() *Agent {
+	return &Agent{
+		backups: backup.NewBackupManager(),
+		logger:  log.New(os.Stdout, "", log.LstdFlags),
+	}
+}
+
+// Run runs the agent until ctx is cancelled. Only one agent may run per
+// SpinDB home.
+func (a *Agent) Run(ctx context.Context) error {
+	if err := utils.EnsureDir(agentDir()); err != nil {
+		return fmt.Errorf("failed to create agent directory: %w", err)
+	}
+
+	lock, err := utils.TryLockFile(filepath.Join(agentDir(), "agent.lock"))
+	if errors.Is(err, utils.ErrLocked) {
+		return fmt.Errorf("another spindb agent is already running")
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if a.status, err = LoadStatus(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	a.status.PID = os.Getpid()
+	a.status.StartedAt = now
+	a.status.Heartbeat = now
+	a.checked = now
+	if err := a.status.save(); err != nil {
+		return err
+	}
+
+	a.logger.Printf("spindb agent started (pid %d)", a.status.PID)
+	a.logSchedules()
+
+	for {
+		// Wake just after each minute boundary, when schedules can match.
+		wait := time.Until(time.Now().Truncate(time.Minute).Add(time.Minute + time.Second))
+		select {
+		case <-ctx.Done():
+			a.status.PID = 0
+			if err := a.status.save(); err != nil {
+				a.logger.Printf("failed to save status: %v", err)
+			}
+			a.logger.Printf("spindb agent stopped")
+			return nil
+		case <-time.After(wait):
+		}
+
+		a.tick(ctx)
+	}
+}
+
+func (a *Agent) logSchedules() {
+	schedules, err := a.backups.Schedules()
+	if err != nil {
+		a.logger.Printf("failed to load schedules: %v", err)
+		return
+	}
+
+	if len(schedules) == 0 {
+		a.logger.Printf("no backup schedules configured")
+	}
+	for _, schedule := range schedules {
+		a.logger.Printf("%s: backing up on '%s' (%s schedule)", schedule.Database, schedule.Schedule.Cron, schedule.Source)
+	}
+}
+
+func (a *Agent) tick(ctx context.Context) {
+	now := time.Now()
+	since := a.checked
+	a.checked = now
+
+	schedules, err := a.backups.Schedules()
+	if err != nil {
+		a.logger.Printf("failed to load schedules: %v", err)
+	}
+
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			break
+		}
+
+		cron, err := ParseCron(schedule.Schedule.Cron)
+		if err != nil {
+			a.logger.Printf("%s: skipping: %v", schedule.Database, err)
+			continue
+		}
+
+		if next := cron.Next(since); next.IsZero() || next.After(now) {
+			continue
+		}
+
+		a.status.Runs[schedule.Database] = a.backup(schedule)
+	}
+
+	a.status.Heartbeat = time.Now()
+	if err := a.status.save(); err != nil {
+		a.logger.Printf("failed to save status: %v", err)
+	}
+}
+
+// backup runs one scheduled backup and then applies the database's
+// retention policy.
+func (a *Agent) backup(schedule backup.ScheduleInfo) *RunStatus {
+	run := &RunStatus{StartedAt: time.Now()}
+	a.logger.Printf("%s: starting scheduled backup", schedule.Database)
+
+	info, err := a.backups.CreateBackup(schedule.Database, &backup.BackupOptions{
+		Compress: schedule.Schedule.Compress,
+	})
+	if err != nil {
+		run.FinishedAt = time.Now()
+		run.Error = err.Error()
+		a.logger.Printf("%s: backup failed: %v", schedule.Database, err)
+		return run
+	}
+
+	run.Backup = info.Name
+	run.Size = info.Size
+	a.logger.Printf("%s: created backup %s (%.2f MB) in %s", schedule.Database, info.Name, float64(info.Size)/(1024*1024), time.Since(run.StartedAt).Round(time.Second))
+
+	pruned, err := a.backups.Prune(schedule.Database, false)
+	run.Pruned = len(pruned)
+	for _, backup := range pruned {
+		a.logger.Printf("%s: pruned backup %s", schedule.Database, backup.Name)
+	}
+
+	run.FinishedAt = time.Now()
+	if err != nil {
+		run.Error = fmt.Sprintf("prune failed: %v", err)
+		a.logger.Printf("%s: prune failed: %v", schedule.Database, err)
+		return run
+	}
+
+	run.Success = true
+	return run
+}
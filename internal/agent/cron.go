@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression: minute, hour, day of month,
+// month and day of week. Fields accept *, lists, ranges and steps, months
+// and weekdays accept names, and the usual @daily style macros are
+// supported.
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func ParseCron(expr string) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression '%s': expected 5 fields (minute hour day month weekday)", expr)
+	}
+
+	var c Cron
+	var err error
+	for _, field := range []struct {
+		name     string
+		value    string
+		min, max int
+		names    map[string]int
+		bits     *uint64
+	}{
+		{"minute", fields[0], 0, 59, nil, &c.minute},
+		{"hour", fields[1], 0, 23, nil, &c.hour},
+		{"day of month", fields[2], 1, 31, nil, &c.dom},
+		{"month", fields[3], 1, 12, monthNames, &c.month},
+		{"day of week", fields[4], 0, 7, dayNames, &c.dow},
+	} {
+		if *field.bits, err = parseCronField(field.value, field.min, field.max, field.names); err != nil {
+			return nil, fmt.Errorf("invalid cron expression '%s': %s: %w", expr, field.name, err)
+		}
+	}
+
+	// 7 is an alias for Sunday.
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+
+	// Like cron, a day field starting with * counts as unrestricted, */2
+	// included, for how the two day fields combine.
+	c.domAny = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	c.dowAny = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return &c, nil
+}
+
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step '%s'", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(from, min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(to, min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+		default:
+			n, err := cronValue(rangePart, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func cronValue(value string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+	}
+
+	return n, nil
+}
+
+// Next returns the first time after t that matches, or the zero time when
+// nothing matches within five years, e.g. for February 30th. Times are
+// wall-clock times: one skipped by a DST change does not run that day, and
+// one repeated by a DST change runs once.
+func (c *Cron) Next(t time.Time) time.Time {
+	after := wallClock(t)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case c.minute&(1<<uint(t.Minute())) == 0, !wallClock(t).After(after):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// dayMatches follows cron: when both day fields are restricted, a day
+// matching either one is enough, otherwise it has to match both.
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+
+	if c.domAny || c.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
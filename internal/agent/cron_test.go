@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * * foo",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@every",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// 2026-01-01 is a Thursday.
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []time.Time
+	}{
+		{
+			name: "every minute starts after from",
+			expr: "* * * * *",
+			from: time.Date(2026, 1, 1, 10, 30, 45, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 1, 10, 31, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 10, 32, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "daily macro",
+			expr: "@daily",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "list, range and step",
+			expr: "0,30 9-17/4 * * *",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 13, 30, 0, 0, time.UTC),
+				time.Date(2026, 1, 1, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "month and weekday names",
+			expr: "0 12 * feb mon-tue",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 2, 2, 12, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 3, 12, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 9, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "7 is sunday",
+			expr: "0 0 * * 7",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 11, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "restricted day of month and weekday match either",
+			expr: "0 0 13 * fri",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 9, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "unrestricted weekday leaves day of month alone",
+			expr: "0 0 1,15 * *",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "step in day of month must match weekday too",
+			expr: "0 0 */2 * mon",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 2, 9, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "step in weekday must match day of month too",
+			expr: "0 0 1-7 * */3",
+			from: from,
+			want: []time.Time{
+				time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 1, 7, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: from,
+			want: []time.Time{
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "never matches",
+			expr: "0 0 30 2 *",
+			from: from,
+			want: []time.Time{{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+
+			next := tt.from
+			for i, want := range tt.want {
+				next = cron.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("run %d: Next = %v, want %v", i+1, next, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCronNextDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want []string
+	}{
+		{
+			name: "skipped time does not run that day",
+			expr: "30 2 * * *",
+			from: time.Date(2026, 3, 29, 0, 0, 0, 0, berlin),
+			want: []string{"2026-03-30 02:30 CEST", "2026-03-31 02:30 CEST"},
+		},
+		{
+			name: "hourly across the skipped hour",
+			expr: "0 * * * *",
+			from: time.Date(2026, 3, 29, 1, 0, 0, 0, berlin),
+			want: []string{"2026-03-29 03:00 CEST", "2026-03-29 04:00 CEST"},
+		},
+		{
+			name: "repeated time runs once",
+			expr: "30 2 * * *",
+			from: time.Date(2026, 10, 25, 0, 0, 0, 0, berlin),
+			want: []string{"2026-10-25 02:30 CET", "2026-10-26 02:30 CET"},
+		},
+		{
+			name: "repeated time runs once after running in summer time",
+			expr: "30 2 * * *",
+			from: time.Date(2026, 10, 25, 2, 30, 0, 0, berlin).Add(-time.Hour),
+			want: []string{"2026-10-26 02:30 CET"},
+		},
+		{
+			name: "hourly across the repeated hour",
+			expr: "0 * * * *",
+			from: time.Date(2026, 10, 25, 1, 0, 0, 0, berlin),
+			want: []string{"2026-10-25 02:00 CEST", "2026-10-25 03:00 CET"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+
+			next := tt.from
+			for i, want := range tt.want {
+				next = cron.Next(next)
+				if got := next.Format("2006-01-02 15:04 MST"); got != want {
+					t.Fatalf("run %d: Next = %s, want %s", i+1, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/awade12/spindb/internal/backup"
+	"github.com/awade12/spindb/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// heartbeatTimeout is how stale the heartbeat may get before the agent is
+// reported as not running. The agent writes it at least once a minute.
+const heartbeatTimeout = 3 * time.Minute
+
+// Status is what the agent records in agent/status.yaml under the SpinDB
+// home for 'spindb backup status' to read.
+type Status struct {
+	PID       int                   `json:"pid" yaml:"pid"`
+	StartedAt time.Time             `json:"started_at" yaml:"started_at"`
+	Heartbeat time.Time             `json:"heartbeat" yaml:"heartbeat"`
+	Runs      map[string]*RunStatus `json:"runs,omitempty" yaml:"runs,omitempty"`
+}
+
+// RunStatus is the outcome of the last scheduled backup of a database.
+type RunStatus struct {
+	StartedAt  time.Time `json:"started_at" yaml:"started_at"`
+	FinishedAt time.Time `json:"finished_at" yaml:"finished_at"`
+	Success    bool      `json:"success" yaml:"success"`
+	Backup     string    `json:"backup,omitempty" yaml:"backup,omitempty"`
+	Size       int64     `json:"size,omitempty" yaml:"size,omitempty"`
+	Pruned     int       `json:"pruned,omitempty" yaml:"pruned,omitempty"`
+	Error      string    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// DatabaseStatus combines a database's schedule with its last run.
+type DatabaseStatus struct {
+	backup.ScheduleInfo `yaml:",inline"`
+	NextRun             *time.Time `json:"next_run,omitempty" yaml:"next_run,omitempty"`
+	LastRun             *RunStatus `json:"last_run,omitempty" yaml:"last_run,omitempty"`
+	Error               string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Report is the answer to 'spindb backup status'.
+type Report struct {
+	Running   bool             `json:"running" yaml:"running"`
+	PID       int              `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Heartbeat *time.Time       `json:"heartbeat,omitempty" yaml:"heartbeat,omitempty"`
+	Databases []DatabaseStatus `json:"databases" yaml:"databases"`
+}
+
+func agentDir() string {
+	return filepath.Join(utils.SpinDBHome(), "agent")
+}
+
+func statusPath() string {
+	return filepath.Join(agentDir(), "status.yaml")
+}
+
+func LoadStatus() (*Status, error) {
+	status := &Status{Runs: make(map[string]*RunStatus)}
+
+	data, err := os.ReadFile(statusPath())
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent status: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, status); err != nil {
+		return nil, fmt.Errorf("failed to parse agent status: %w", err)
+	}
+	if status.Runs == nil {
+		status.Runs = make(map[string]*RunStatus)
+	}
+
+	return status, nil
+}
+
+func (s *Status) save() error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal agent status: %w", err)
+	}
+
+	return utils.WriteFileAtomic(statusPath(), data, 0644)
+}
+
+func (s *Status) Running() bool {
+	return s.PID != 0 && time.Since(s.Heartbeat) < heartbeatTimeout
+}
+
+// BackupStatus reports whether the agent is running and, for every
+// scheduled database, when it last ran and when it runs next.
+func BackupStatus() (*Report, error) {
+	status, err := LoadStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := backup.NewBackupManager().Schedules()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Running: status.Running(), Databases: []DatabaseStatus{}}
+	if report.Running {
+		report.PID = status.PID
+		report.Heartbeat = &status.Heartbeat
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		entry := DatabaseStatus{ScheduleInfo: schedule, LastRun: status.Runs[schedule.Database]}
+
+		if cron, err := ParseCron(schedule.Schedule.Cron); err != nil {
+			entry.Error = err.Error()
+		} else if next := cron.Next(now); !next.IsZero() {
+			entry.NextRun = &next
+		}
+
+		report.Databases = append(report.Databases, entry)
+	}
+
+	return report, nil
+}
@@ -1,15 +1,20 @@
 package backup
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/utils"
 )
 
 type BackupManager struct {
@@ -17,14 +22,10 @@ type BackupManager struct {
 	store     *config.DatabaseStore
 }
 
+// BackupInfo is a backup's manifest together with where its file is.
 type BackupInfo struct {
-	Name       string    `yaml:"name"`
-	Database   string    `yaml:"database"`
-	Type       string    `yaml:"type"`
-	Size       int64     `yaml:"size"`
-	CreatedAt  time.Time `yaml:"created_at"`
-	FilePath   string    `yaml:"file_path"`
-	Compressed bool      `yaml:"compressed"`
+	Manifest `yaml:",inline"`
+	FilePath string `json:"file_path" yaml:"file_path"`
 }
 
 func NewBackupManager() *BackupManager {
@@ -40,81 +41,102 @@ func NewBackupManager() *BackupManager {
 }
 
 func (bm *BackupManager) CreateBackup(dbName string, options *BackupOptions) (*BackupInfo, error) {
-	registry, err := bm.store.Load()
+	database, err := bm.findDatabase(dbName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load database registry: %w", err)
-	}
-
-	var db *config.DatabaseConfig
-	for _, database := range registry.Databases {
-		if database.Name == dbName {
-			db = &database
-			break
-		}
+		return nil, err
 	}
 
-	if db == nil {
-		return nil, fmt.Errorf("database '%s' not found", dbName)
+	engine, err := db.GetEngine(database.Type)
+	if err != nil {
+		return nil, err
 	}
 
-	timestamp := time.Now().Format("20060102_150405")
-	backupName := fmt.Sprintf("%s_%s", dbName, timestamp)
-
-	var backupPath string
-	var backupErr error
-
-	switch db.Type {
-	case "postgres":
-		backupPath, backupErr = bm.backupPostgres(db, backupName, options)
-	case "mysql":
-		backupPath, backupErr = bm.backupMySQL(db, backupName, options)
-	case "sqlite":
-		backupPath, backupErr = bm.backupSQLite(db, backupName, options)
-	default:
-		return nil, fmt.Errorf("unsupported database type: %s", db.Type)
-	}
+	createdAt := time.Now()
+	baseName := fmt.Sprintf("%s_%s", dbName, createdAt.Format(timestampLayout))
 
+	backupPath, checksum, backupErr := bm.writeBackup(engine, database, baseName, options)
 	if backupErr != nil {
 		return nil, fmt.Errorf("failed to create backup: %w", backupErr)
 	}
+	backupName, _ := splitBackupFile(filepath.Base(backupPath))
 
 	fileInfo, err := os.Stat(backupPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get backup file info: %w", err)
 	}
 
-	backupInfo := &BackupInfo{
-		Name:       backupName,
-		Database:   dbName,
-		Type:       db.Type,
-		Size:       fileInfo.Size(),
-		CreatedAt:  time.Now(),
-		FilePath:   backupPath,
-		Compressed: options != nil && options.Compress,
+	manifest := Manifest{
+		SchemaVersion: ManifestVersion,
+		Name:          backupName,
+		File:          filepath.Base(backupPath),
+		Database:      dbName,
+		Type:          database.Type,
+		Version:       database.Version,
+		User:          database.User,
+		Extensions:    database.Extensions,
+		Size:          fileInfo.Size(),
+		SHA256:        checksum,
+		Compressed:    options != nil && options.Compress,
+		SchemaOnly:    options != nil && options.SchemaOnly,
+		DataOnly:      options != nil && options.DataOnly,
+		SpinDBVersion: SpinDBVersion,
+		CreatedAt:     createdAt,
 	}
 
-	return backupInfo, nil
+	if err := writeManifest(bm.backupDir, &manifest); err != nil {
+		os.Remove(backupPath)
+		return nil, err
+	}
+
+	return &BackupInfo{Manifest: manifest, FilePath: backupPath}, nil
 }
 
 func (bm *BackupManager) RestoreBackup(backupName, targetDbName string) error {
-	backupPath := filepath.Join(bm.backupDir, backupName)
+	backup, err := bm.GetBackup(backupName)
+	if err != nil {
+		return err
+	}
 
-	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
-		return fmt.Errorf("backup file '%s' not found", backupName)
+	engine, err := db.GetEngine(backup.Type)
+	if err != nil {
+		return fmt.Errorf("unable to determine backup type for: %s", backup.Name)
 	}
 
-	dbType := bm.detectBackupType(backupName)
+	target, err := bm.findDatabase(targetDbName)
+	if err != nil {
+		return fmt.Errorf("target database '%s' not found", targetDbName)
+	}
 
-	switch dbType {
-	case "postgres":
-		return bm.restorePostgres(backupPath, targetDbName)
-	case "mysql":
-		return bm.restoreMySQL(backupPath, targetDbName)
-	case "sqlite":
-		return bm.restoreSQLite(backupPath, targetDbName)
-	default:
-		return fmt.Errorf("unable to determine backup type for: %s", backupName)
+	if target.Type != engine.Name() {
+		return fmt.Errorf("cannot restore %s backup into %s database '%s'", engine.Name(), target.Type, targetDbName)
 	}
+
+	checksum, _, err := fileChecksum(backup.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read backup file: %w", err)
+	}
+	if checksum != backup.SHA256 {
+		return fmt.Errorf("backup '%s' is corrupt: checksum does not match its manifest", backup.Name)
+	}
+
+	return bm.readBackup(engine, target, backup.FilePath, backup.Compressed, false)
+}
+
+// GetBackup looks a backup up by name. The file name of the backup is
+// accepted too.
+func (bm *BackupManager) GetBackup(name string) (*BackupInfo, error) {
+	backups, err := bm.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, backup := range backups {
+		if backup.Name == name || backup.File == name {
+			return backup, nil
+		}
+	}
+
+	return nil, fmt.Errorf("backup '%s' not found", name)
 }
 
 func (bm *BackupManager) ListBackups() ([]*BackupInfo, error) {
@@ -125,32 +147,25 @@ func (bm *BackupManager) ListBackups() ([]*BackupInfo, error) {
 
 	var backups []*BackupInfo
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), manifestExtension) || strings.HasPrefix(file.Name(), ".") {
 			continue
 		}
 
-		info, err := file.Info()
+		// One stray or damaged .yaml file must not hide every other backup.
+		manifest, err := readManifest(filepath.Join(bm.backupDir, file.Name()))
 		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: %v\n", file.Name(), err)
 			continue
 		}
-
-		dbName, timestamp := bm.parseBackupName(file.Name())
-		if dbName == "" {
+		if manifest.Name == "" || manifest.File == "" {
+			fmt.Fprintf(os.Stderr, "⚠️  Skipping %s: not a backup manifest\n", file.Name())
 			continue
 		}
 
-		parsedTime, _ := time.Parse("20060102_150405", timestamp)
-
-		backup := &BackupInfo{
-			Name:      strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())),
-			Database:  dbName,
-			Type:      bm.detectBackupType(file.Name()),
-			Size:      info.Size(),
-			CreatedAt: parsedTime,
-			FilePath:  filepath.Join(bm.backupDir, file.Name()),
-		}
-
-		backups = append(backups, backup)
+		backups = append(backups, &BackupInfo{
+			Manifest: *manifest,
+			FilePath: filepath.Join(bm.backupDir, manifest.File),
+		})
 	}
 
 	sort.Slice(backups, func(i, j int) bool {
@@ -161,376 +176,166 @@ func (bm *BackupManager) ListBackups() ([]*BackupInfo, error) {
 }
 
 func (bm *BackupManager) DeleteBackup(backupName string) error {
-	backupPath := filepath.Join(bm.backupDir, backupName)
-
-	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
-		return fmt.Errorf("backup '%s' not found", backupName)
-	}
-
-	return os.Remove(backupPath)
-}
-
-func (bm *BackupManager) backupPostgres(db *config.DatabaseConfig, backupName string, options *BackupOptions) (string, error) {
-	backupPath := filepath.Join(bm.backupDir, backupName+".sql")
-	if options != nil && options.Compress {
-		backupPath += ".gz"
+	backup, err := bm.GetBackup(backupName)
+	if err != nil {
+		return err
 	}
 
-	cmd := []string{
-		"pg_dump",
-		"-h", "localhost",
-		"-p", fmt.Sprintf("%d", db.Port),
-		"-U", db.User,
-		"-d", db.Name,
-		"--no-password",
+	if err := os.Remove(backup.FilePath); err != nil && !os.IsNotExist(err) {
+		return err
 	}
 
-	if options != nil && options.SchemaOnly {
-		cmd = append(cmd, "--schema-only")
-	}
+	return os.Remove(manifestPath(bm.backupDir, backup.Name))
+}
 
-	if options != nil && options.DataOnly {
-		cmd = append(cmd, "--data-only")
+func (bm *BackupManager) findDatabase(name string) (*config.DatabaseConfig, error) {
+	registry, err := bm.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database registry: %w", err)
 	}
 
-	pgCmd := exec.Command(cmd[0], cmd[1:]...)
-	pgCmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", db.Password))
-
-	if options != nil && options.Compress {
-		gzipCmd := exec.Command("gzip")
-
-		pgCmd.Stdout, _ = gzipCmd.StdinPipe()
-		gzipOutput, err := os.Create(backupPath)
-		if err != nil {
-			return "", err
-		}
-		defer gzipOutput.Close()
-
-		gzipCmd.Stdout = gzipOutput
-
-		if err := gzipCmd.Start(); err != nil {
-			return "", err
-		}
-
-		if err := pgCmd.Run(); err != nil {
-			return "", err
-		}
-
-		pgCmd.Stdout.(*os.File).Close()
-
-		if err := gzipCmd.Wait(); err != nil {
-			return "", err
-		}
-	} else {
-		output, err := os.Create(backupPath)
-		if err != nil {
-			return "", err
-		}
-		defer output.Close()
-
-		pgCmd.Stdout = output
-
-		if err := pgCmd.Run(); err != nil {
-			return "", err
+	for _, database := range registry.Databases {
+		if database.Name == name {
+			return &database, nil
 		}
 	}
 
-	return backupPath, nil
+	return nil, fmt.Errorf("database '%s' not found", name)
 }
 
-func (bm *BackupManager) backupMySQL(db *config.DatabaseConfig, backupName string, options *BackupOptions) (string, error) {
-	backupPath := filepath.Join(bm.backupDir, backupName+".sql")
+// writeBackup dumps database into a new backup file named after baseName and
+// returns its path and checksum.
+func (bm *BackupManager) writeBackup(engine db.Engine, database *config.DatabaseConfig, baseName string, options *BackupOptions) (string, string, error) {
+	extension := engine.BackupExtension()
 	if options != nil && options.Compress {
-		backupPath += ".gz"
+		extension += ".gz"
 	}
 
-	cmd := []string{
-		"mysqldump",
-		"-h", "localhost",
-		"-P", fmt.Sprintf("%d", db.Port),
-		"-u", db.User,
-		fmt.Sprintf("-p%s", db.Password),
-		db.Name,
-	}
-
-	if options != nil && options.SchemaOnly {
-		cmd = append(cmd, "--no-data")
-	}
-
-	if options != nil && options.DataOnly {
-		cmd = append(cmd, "--no-create-info")
-	}
-
-	mysqlCmd := exec.Command(cmd[0], cmd[1:]...)
-
-	if options != nil && options.Compress {
-		gzipCmd := exec.Command("gzip")
-
-		mysqlCmd.Stdout, _ = gzipCmd.StdinPipe()
-		gzipOutput, err := os.Create(backupPath)
-		if err != nil {
-			return "", err
-		}
-		defer gzipOutput.Close()
-
-		gzipCmd.Stdout = gzipOutput
-
-		if err := gzipCmd.Start(); err != nil {
-			return "", err
-		}
-
-		if err := mysqlCmd.Run(); err != nil {
-			return "", err
-		}
-
-		mysqlCmd.Stdout.(*os.File).Close()
-
-		if err := gzipCmd.Wait(); err != nil {
-			return "", err
-		}
-	} else {
-		output, err := os.Create(backupPath)
-		if err != nil {
-			return "", err
-		}
-		defer output.Close()
-
-		mysqlCmd.Stdout = output
-
-		if err := mysqlCmd.Run(); err != nil {
-			return "", err
-		}
+	backupPath, output, err := bm.reserveBackup(baseName, extension)
+	if err != nil {
+		return "", "", err
 	}
+	partialPath := output.Name()
+	defer output.Close()
+	defer os.Remove(partialPath)
 
-	return backupPath, nil
-}
-
-func (bm *BackupManager) backupSQLite(db *config.DatabaseConfig, backupName string, options *BackupOptions) (string, error) {
-	backupPath := filepath.Join(bm.backupDir, backupName+".db")
+	// The checksum covers the file as written, after compression.
+	hash := sha256.New()
+	var w io.Writer = io.MultiWriter(output, hash)
+	var gz *gzip.Writer
 	if options != nil && options.Compress {
-		backupPath += ".gz"
+		gz = gzip.NewWriter(w)
+		w = gz
 	}
 
-	if options != nil && options.Compress {
-		cmd := exec.Command("gzip", "-c", db.FilePath)
-		output, err := os.Create(backupPath)
-		if err != nil {
-			return "", err
-		}
-		defer output.Close()
-
-		cmd.Stdout = output
-		if err := cmd.Run(); err != nil {
-			return "", err
-		}
-	} else {
-		srcFile, err := os.Open(db.FilePath)
-		if err != nil {
-			return "", err
-		}
-		defer srcFile.Close()
-
-		dstFile, err := os.Create(backupPath)
-		if err != nil {
-			return "", err
-		}
-		defer dstFile.Close()
-
-		if _, err := srcFile.WriteTo(dstFile); err != nil {
-			return "", err
+	var dumpOptions *db.DumpOptions
+	if options != nil {
+		dumpOptions = &db.DumpOptions{
+			SchemaOnly: options.SchemaOnly,
+			DataOnly:   options.DataOnly,
 		}
 	}
 
-	return backupPath, nil
-}
-
-func (bm *BackupManager) restorePostgres(backupPath, targetDbName string) error {
-	registry, err := bm.store.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load database registry: %w", err)
+	if err := engine.Backup(database, dumpOptions, w); err != nil {
+		return "", "", err
 	}
 
-	var db *config.DatabaseConfig
-	for _, database := range registry.Databases {
-		if database.Name == targetDbName {
-			db = &database
-			break
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return "", "", err
 		}
 	}
 
-	if db == nil {
-		return fmt.Errorf("target database '%s' not found", targetDbName)
+	if err := output.Sync(); err != nil {
+		return "", "", err
 	}
 
-	var cmd *exec.Cmd
-	if strings.HasSuffix(backupPath, ".gz") {
-		zcat := exec.Command("zcat", backupPath)
-		psql := exec.Command("psql",
-			"-h", "localhost",
-			"-p", fmt.Sprintf("%d", db.Port),
-			"-U", db.User,
-			"-d", db.Name,
-		)
-		psql.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", db.Password))
-
-		psql.Stdin, _ = zcat.StdoutPipe()
-
-		if err := zcat.Start(); err != nil {
-			return err
-		}
-
-		if err := psql.Run(); err != nil {
-			return err
-		}
+	if err := output.Close(); err != nil {
+		return "", "", err
+	}
 
-		return zcat.Wait()
-	} else {
-		cmd = exec.Command("psql",
-			"-h", "localhost",
-			"-p", fmt.Sprintf("%d", db.Port),
-			"-U", db.User,
-			"-d", db.Name,
-			"-f", backupPath,
-		)
-		cmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", db.Password))
+	if err := os.Rename(partialPath, backupPath); err != nil {
+		return "", "", err
 	}
 
-	return cmd.Run()
+	return backupPath, hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-func (bm *BackupManager) restoreMySQL(backupPath, targetDbName string) error {
-	registry, err := bm.store.Load()
+// readBackup restores a backup file into database. With strict, engines that
+// would skip failing statements stop at the first one instead.
+// reserveBackup picks a backup name that no backup, manifest or dump in
+// progress uses yet, adding a counter to baseName when backups of the same
+// database start within a second, and creates the partial file the dump is
+// written to. The dump goes to a dot file that listing and migration skip,
+// and only takes the backup's name once it is complete.
+func (bm *BackupManager) reserveBackup(baseName, extension string) (string, *os.File, error) {
+	lock, err := utils.LockFile(filepath.Join(bm.backupDir, ".lock"))
 	if err != nil {
-		return fmt.Errorf("failed to load database registry: %w", err)
+		return "", nil, err
 	}
+	defer lock.Unlock()
 
-	var db *config.DatabaseConfig
-	for _, database := range registry.Databases {
-		if database.Name == targetDbName {
-			db = &database
-			break
+	for i := 1; ; i++ {
+		name := baseName
+		if i > 1 {
+			name = fmt.Sprintf("%s_%d", baseName, i)
 		}
-	}
-
-	if db == nil {
-		return fmt.Errorf("target database '%s' not found", targetDbName)
-	}
-
-	var cmd *exec.Cmd
-	if strings.HasSuffix(backupPath, ".gz") {
-		zcat := exec.Command("zcat", backupPath)
-		mysql := exec.Command("mysql",
-			"-h", "localhost",
-			"-P", fmt.Sprintf("%d", db.Port),
-			"-u", db.User,
-			fmt.Sprintf("-p%s", db.Password),
-			db.Name,
-		)
 
-		mysql.Stdin, _ = zcat.StdoutPipe()
-
-		if err := zcat.Start(); err != nil {
-			return err
+		taken, err := bm.nameTaken(name)
+		if err != nil {
+			return "", nil, err
 		}
-
-		if err := mysql.Run(); err != nil {
-			return err
+		if taken {
+			continue
 		}
 
-		return zcat.Wait()
-	} else {
-		cmd = exec.Command("mysql",
-			"-h", "localhost",
-			"-P", fmt.Sprintf("%d", db.Port),
-			"-u", db.User,
-			fmt.Sprintf("-p%s", db.Password),
-			db.Name,
-		)
-
-		input, err := os.Open(backupPath)
+		backupPath := filepath.Join(bm.backupDir, name+extension)
+		partialPath := filepath.Join(bm.backupDir, "."+name+extension+".partial")
+		output, err := os.OpenFile(partialPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
 		if err != nil {
-			return err
+			return "", nil, err
 		}
-		defer input.Close()
-
-		cmd.Stdin = input
+		return backupPath, output, nil
 	}
-
-	return cmd.Run()
 }
 
-func (bm *BackupManager) restoreSQLite(backupPath, targetDbName string) error {
-	registry, err := bm.store.Load()
+func (bm *BackupManager) nameTaken(name string) (bool, error) {
+	files, err := os.ReadDir(bm.backupDir)
 	if err != nil {
-		return fmt.Errorf("failed to load database registry: %w", err)
+		return false, fmt.Errorf("failed to read backup directory: %w", err)
 	}
 
-	var db *config.DatabaseConfig
-	for _, database := range registry.Databases {
-		if database.Name == targetDbName {
-			db = &database
-			break
+	for _, file := range files {
+		if strings.HasPrefix(file.Name(), name+".") || strings.HasPrefix(file.Name(), "."+name+".") {
+			return true, nil
 		}
 	}
 
-	if db == nil {
-		return fmt.Errorf("target database '%s' not found", targetDbName)
-	}
-
-	if strings.HasSuffix(backupPath, ".gz") {
-		cmd := exec.Command("gunzip", "-c", backupPath)
-		output, err := os.Create(db.FilePath)
-		if err != nil {
-			return err
-		}
-		defer output.Close()
-
-		cmd.Stdout = output
-		return cmd.Run()
-	} else {
-		srcFile, err := os.Open(backupPath)
-		if err != nil {
-			return err
-		}
-		defer srcFile.Close()
-
-		dstFile, err := os.Create(db.FilePath)
-		if err != nil {
-			return err
-		}
-		defer dstFile.Close()
-
-		_, err = srcFile.WriteTo(dstFile)
-		return err
-	}
+	return false, nil
 }
 
-func (bm *BackupManager) detectBackupType(filename string) string {
-	if strings.Contains(filename, "_postgres_") || strings.Contains(filename, "_pg_") {
-		return "postgres"
-	}
-	if strings.Contains(filename, "_mysql_") {
-		return "mysql"
-	}
-	if strings.HasSuffix(filename, ".db") || strings.HasSuffix(filename, ".db.gz") {
-		return "sqlite"
+func (bm *BackupManager) readBackup(engine db.Engine, database *config.DatabaseConfig, backupPath string, compressed, strict bool) error {
+	input, err := os.Open(backupPath)
+	if err != nil {
+		return err
 	}
-	return "unknown"
-}
+	defer input.Close()
 
-func (bm *BackupManager) parseBackupName(filename string) (dbName, timestamp string) {
-	base := strings.TrimSuffix(filename, filepath.Ext(filename))
-	if strings.HasSuffix(base, ".gz") {
-		base = strings.TrimSuffix(base, ".gz")
+	var r io.Reader = input
+	if compressed {
+		gz, err := gzip.NewReader(input)
+		if err != nil {
+			return fmt.Errorf("failed to open compressed backup: %w", err)
+		}
+		defer gz.Close()
+		r = gz
 	}
 
-	parts := strings.Split(base, "_")
-	if len(parts) >= 3 {
-		timestamp = parts[len(parts)-2] + "_" + parts[len(parts)-1]
-		dbName = strings.Join(parts[:len(parts)-2], "_")
+	if restorer, ok := engine.(db.StrictRestoringEngine); ok && strict {
+		return restorer.RestoreStrict(database, r)
 	}
 
-	return
+	return engine.Restore(database, r)
 }
 
 type BackupOptions struct {
@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReserveBackupNeverReusesAName(t *testing.T) {
+	dir := t.TempDir()
+	bm := &BackupManager{backupDir: dir}
+
+	// A finished backup with its manifest, and one of another format.
+	for _, file := range []string{"app_20260101_120000.sql", "app_20260101_120000.yaml", "app_20260101_120000_2.sql.gz"} {
+		if err := os.WriteFile(filepath.Join(dir, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var paths []string
+	for i := 0; i < 2; i++ {
+		path, output, err := bm.reserveBackup("app_20260101_120000", ".sql")
+		if err != nil {
+			t.Fatalf("reserveBackup: %v", err)
+		}
+		output.Close()
+		paths = append(paths, filepath.Base(path))
+	}
+
+	// The second reservation sees the first one's dump in progress.
+	want := []string{"app_20260101_120000_3.sql", "app_20260101_120000_4.sql"}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("reservation %d = %s, want %s", i+1, paths[i], want[i])
+		}
+	}
+
+	path, output, err := bm.reserveBackup("other_20260101_120000", ".dump")
+	if err != nil {
+		t.Fatalf("reserveBackup: %v", err)
+	}
+	output.Close()
+	if got := filepath.Base(path); got != "other_20260101_120000.dump" {
+		t.Errorf("free name = %s, want other_20260101_120000.dump", got)
+	}
+}
@@ -0,0 +1,289 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ManifestVersion   = 1
+	manifestExtension = ".yaml"
+	timestampLayout   = "20060102_150405"
+)
+
+// SpinDBVersion is recorded in every manifest. The cmd package sets it from
+// the version injected at build time.
+var SpinDBVersion = "dev"
+
+// Manifest is the sidecar written next to every backup file, named after
+// the backup with a .yaml extension. Listing, restoring and verifying read
+// it instead of guessing from the file name.
+type Manifest struct {
+	SchemaVersion int       `json:"schema_version" yaml:"schema_version"`
+	Name          string    `json:"name" yaml:"name"`
+	File          string    `json:"file" yaml:"file"`
+	Database      string    `json:"database" yaml:"database"`
+	Type          string    `json:"type" yaml:"type"`
+	Version       string    `json:"version,omitempty" yaml:"version,omitempty"`
+	User          string    `json:"user,omitempty" yaml:"user,omitempty"`
+	Extensions    []string  `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Size          int64     `json:"size" yaml:"size"`
+	SHA256        string    `json:"sha256" yaml:"sha256"`
+	Compressed    bool      `json:"compressed" yaml:"compressed"`
+	SchemaOnly    bool      `json:"schema_only,omitempty" yaml:"schema_only,omitempty"`
+	DataOnly      bool      `json:"data_only,omitempty" yaml:"data_only,omitempty"`
+	SpinDBVersion string    `json:"spindb_version,omitempty" yaml:"spindb_version,omitempty"`
+	Migrated      bool      `json:"migrated,omitempty" yaml:"migrated,omitempty"`
+	CreatedAt     time.Time `json:"created_at" yaml:"created_at"`
+
+	Verification *Verification `json:"verification,omitempty" yaml:"verification,omitempty"`
+}
+
+func manifestPath(backupDir, name string) string {
+	return filepath.Join(backupDir, name+manifestExtension)
+}
+
+func readManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest %s: %w", path, err)
+	}
+
+	if manifest.SchemaVersion > ManifestVersion {
+		return nil, fmt.Errorf("backup manifest %s has version %d, newer than this spindb supports (%d)", path, manifest.SchemaVersion, ManifestVersion)
+	}
+
+	return &manifest, nil
+}
+
+func writeManifest(backupDir string, manifest *Manifest) error {
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup manifest: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(manifestPath(backupDir, manifest.Name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+
+	return nil
+}
+
+func fileChecksum(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// MigrateBackups writes a manifest for every backup file in backupDir that
+// was created before manifests existed, inferring its database and engine
+// from the file name, the registry and the dump header. Only files with an
+// engine's backup extension count as backups. It returns the files it
+// migrated, or would migrate with dryRun.
+func MigrateBackups(backupDir string, dryRun bool) ([]string, error) {
+	files, err := os.ReadDir(backupDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	described := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), manifestExtension) {
+			if manifest, err := readManifest(filepath.Join(backupDir, file.Name())); err == nil {
+				described[manifest.File] = true
+			}
+		}
+	}
+
+	var registry *config.DatabaseRegistry
+	var migrated []string
+	for _, file := range files {
+		if file.IsDir() || described[file.Name()] || strings.HasSuffix(file.Name(), manifestExtension) || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		if _, extension := splitBackupFile(file.Name()); !isBackupExtension(extension) {
+			continue
+		}
+
+		if registry == nil {
+			if registry, err = config.NewDatabaseStore().Load(); err != nil {
+				return migrated, fmt.Errorf("failed to load database registry: %w", err)
+			}
+		}
+
+		manifest, err := inferManifest(backupDir, file, registry)
+		if err != nil {
+			return migrated, err
+		}
+
+		if _, err := os.Stat(manifestPath(backupDir, manifest.Name)); err == nil {
+			continue
+		}
+
+		if !dryRun {
+			if err := writeManifest(backupDir, manifest); err != nil {
+				return migrated, err
+			}
+		}
+		migrated = append(migrated, filepath.Join(backupDir, file.Name()))
+	}
+
+	return migrated, nil
+}
+
+func inferManifest(backupDir string, file os.DirEntry, registry *config.DatabaseRegistry) (*Manifest, error) {
+	path := filepath.Join(backupDir, file.Name())
+
+	checksum, size, err := fileChecksum(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum %s: %w", path, err)
+	}
+
+	name, extension := splitBackupFile(file.Name())
+	dbName, createdAt := parseBackupName(name)
+
+	if createdAt.IsZero() {
+		if info, err := file.Info(); err == nil {
+			createdAt = info.ModTime()
+		}
+	}
+
+	manifest := &Manifest{
+		SchemaVersion: ManifestVersion,
+		Name:          name,
+		File:          file.Name(),
+		Database:      dbName,
+		Type:          "unknown",
+		Size:          size,
+		SHA256:        checksum,
+		Compressed:    strings.HasSuffix(file.Name(), ".gz"),
+		Migrated:      true,
+		CreatedAt:     createdAt,
+	}
+
+	var candidates []string
+	for _, engine := range db.Engines() {
+		if engine.BackupExtension() == extension {
+			candidates = append(candidates, engine.Name())
+		}
+	}
+
+	for _, database := range registry.Databases {
+		if database.Name == dbName && slices.Contains(candidates, database.Type) {
+			manifest.Type = database.Type
+			manifest.Version = database.Version
+			manifest.User = database.User
+			manifest.Extensions = database.Extensions
+			return manifest, nil
+		}
+	}
+
+	switch {
+	case len(candidates) == 1:
+		manifest.Type = candidates[0]
+	case len(candidates) > 1:
+		if dumpType := sniffDumpType(path, manifest.Compressed); slices.Contains(candidates, dumpType) {
+			manifest.Type = dumpType
+		}
+	}
+
+	return manifest, nil
+}
+
+func isBackupExtension(extension string) bool {
+	for _, engine := range db.Engines() {
+		if engine.BackupExtension() != "" && engine.BackupExtension() == extension {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffDumpType tells SQL dumps apart by the header comment each dump tool
+// writes on its first lines.
+func sniffDumpType(path string, compressed bool) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return ""
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(r, 64*1024))
+	for i := 0; i < 10 && scanner.Scan(); i++ {
+		line := scanner.Text()
+		switch {
+		case strings.Contains(line, "PostgreSQL database dump"):
+			return "postgres"
+		case strings.Contains(line, "MariaDB dump"):
+			return "mariadb"
+		case strings.Contains(line, "MySQL dump"):
+			return "mysql"
+		}
+	}
+
+	return ""
+}
+
+// splitBackupFile splits a backup file name into the backup name and the
+// engine extension, dropping a trailing .gz.
+func splitBackupFile(filename string) (name, extension string) {
+	base := strings.TrimSuffix(filename, ".gz")
+	extension = filepath.Ext(base)
+	return strings.TrimSuffix(base, extension), extension
+}
+
+// parseBackupName splits <database>_<YYYYMMDD>_<HHMMSS> back into its parts.
+// Database names may contain underscores, so the timestamp is taken from
+// the end.
+func parseBackupName(name string) (string, time.Time) {
+	if len(name) > len(timestampLayout)+1 {
+		split := len(name) - len(timestampLayout)
+		if createdAt, err := time.ParseInLocation(timestampLayout, name[split:], time.Local); err == nil && name[split-1] == '_' {
+			return name[:split-1], createdAt
+		}
+	}
+	return name, time.Time{}
+}
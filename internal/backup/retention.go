@@ -0,0 +1,255 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/environment"
+)
+
+const (
+	PolicySourceDatabase    = "database"
+	PolicySourceEnvironment = "environment"
+	PolicySourceGlobal      = "global"
+)
+
+// PolicyInfo is the retention policy in effect for one database's backups.
+type PolicyInfo struct {
+	Database    string                  `json:"database" yaml:"database"`
+	Source      string                  `json:"source" yaml:"source"`
+	Environment string                  `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Policy      *config.RetentionPolicy `json:"policy" yaml:"policy"`
+}
+
+// policies resolves retention policies, most specific first: the database's
+// own, then the first environment containing it that sets one, then the
+// global one from the config.
+type policies struct {
+	databases    map[string]*config.RetentionPolicy
+	environments []*environment.Environment
+	global       config.RetentionPolicy
+}
+
+func (bm *BackupManager) loadPolicies() (*policies, error) {
+	registry, err := bm.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database registry: %w", err)
+	}
+
+	p := &policies{
+		databases: make(map[string]*config.RetentionPolicy),
+		global:    config.Load().Backup.Retention,
+	}
+
+	for _, database := range registry.Databases {
+		if !database.Retention.IsZero() {
+			p.databases[database.Name] = database.Retention
+		}
+	}
+
+	environments, err := environment.NewEnvironmentManager().ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range environments {
+		if !env.Retention.IsZero() {
+			p.environments = append(p.environments, env)
+		}
+	}
+
+	return p, nil
+}
+
+func (p *policies) resolve(dbName string) PolicyInfo {
+	if policy, ok := p.databases[dbName]; ok {
+		return PolicyInfo{Database: dbName, Source: PolicySourceDatabase, Policy: policy}
+	}
+
+	for _, env := range p.environments {
+		if _, ok := env.Databases[dbName]; ok {
+			return PolicyInfo{Database: dbName, Source: PolicySourceEnvironment, Environment: env.Name, Policy: env.Retention}
+		}
+	}
+
+	global := p.global
+	return PolicyInfo{Database: dbName, Source: PolicySourceGlobal, Policy: &global}
+}
+
+// Policies lists the policy in effect for every registered database and
+// every database that still has backups.
+func (bm *BackupManager) Policies() ([]PolicyInfo, error) {
+	p, err := bm.loadPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := bm.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database registry: %w", err)
+	}
+
+	backups, err := bm.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, database := range registry.Databases {
+		names[database.Name] = true
+	}
+	for _, backup := range backups {
+		names[backup.Database] = true
+	}
+
+	infos := []PolicyInfo{}
+	for name := range names {
+		infos = append(infos, p.resolve(name))
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Database < infos[j].Database
+	})
+
+	return infos, nil
+}
+
+// SetDatabaseRetention sets the retention policy of one database. A nil
+// policy removes it, so the environment or global policy applies again.
+func (bm *BackupManager) SetDatabaseRetention(dbName string, policy *config.RetentionPolicy) error {
+	return bm.store.Update(func(registry *config.DatabaseRegistry) error {
+		for i := range registry.Databases {
+			if registry.Databases[i].Name == dbName {
+				registry.Databases[i].Retention = policy
+				return nil
+			}
+		}
+		return fmt.Errorf("database '%s' not found", dbName)
+	})
+}
+
+// Prune deletes the backups the retention policies do not keep, for one
+// database or, with an empty dbName, for all of them. With dryRun nothing
+// is deleted. It returns the backups pruned, or that would be.
+func (bm *BackupManager) Prune(dbName string, dryRun bool) ([]*BackupInfo, error) {
+	p, err := bm.loadPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	backups, err := bm.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]*BackupInfo)
+	var names []string
+	for _, backup := range backups {
+		if dbName != "" && backup.Database != dbName {
+			continue
+		}
+		if _, ok := groups[backup.Database]; !ok {
+			names = append(names, backup.Database)
+		}
+		groups[backup.Database] = append(groups[backup.Database], backup)
+	}
+	sort.Strings(names)
+
+	var pruned []*BackupInfo
+	for _, name := range names {
+		info := p.resolve(name)
+		if info.Policy.IsZero() {
+			continue
+		}
+
+		if err := info.Policy.Validate(); err != nil {
+			return pruned, fmt.Errorf("invalid %s retention policy for '%s': %w", info.Source, name, err)
+		}
+
+		for _, backup := range prunable(groups[name], info.Policy) {
+			if !dryRun {
+				if err := bm.DeleteBackup(backup.Name); err != nil {
+					return pruned, fmt.Errorf("failed to delete backup '%s': %w", backup.Name, err)
+				}
+			}
+			pruned = append(pruned, backup)
+		}
+	}
+
+	return pruned, nil
+}
+
+// prunable applies a policy to one database's backups, newest first, and
+// returns those it does not keep. The newest backup is always kept.
+func prunable(backups []*BackupInfo, policy *config.RetentionPolicy) []*BackupInfo {
+	keep := make([]bool, len(backups))
+
+	if policy.KeepsAll() {
+		for i := range keep {
+			keep[i] = true
+		}
+	} else {
+		for i := 0; i < len(backups) && i < policy.KeepLast; i++ {
+			keep[i] = true
+		}
+
+		keepPerPeriod(backups, keep, policy.KeepDaily, func(t time.Time) string {
+			return t.Format("2006-01-02")
+		})
+		keepPerPeriod(backups, keep, policy.KeepWeekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		})
+		keepPerPeriod(backups, keep, policy.KeepMonthly, func(t time.Time) string {
+			return t.Format("2006-01")
+		})
+	}
+
+	if len(keep) > 0 {
+		keep[0] = true
+	}
+
+	if maxSize, _ := policy.MaxSizeBytes(); maxSize > 0 {
+		var total int64
+		for i, backup := range backups {
+			if keep[i] {
+				total += backup.Size
+			}
+		}
+
+		for i := len(backups) - 1; i > 0 && total > maxSize; i-- {
+			if keep[i] {
+				keep[i] = false
+				total -= backups[i].Size
+			}
+		}
+	}
+
+	var doomed []*BackupInfo
+	for i, backup := range backups {
+		if !keep[i] {
+			doomed = append(doomed, backup)
+		}
+	}
+
+	return doomed
+}
+
+// keepPerPeriod keeps the newest backup of each of the count most recent
+// periods that have one.
+func keepPerPeriod(backups []*BackupInfo, keep []bool, count int, period func(time.Time) string) {
+	seen := make(map[string]bool)
+	for i, backup := range backups {
+		if len(seen) >= count {
+			return
+		}
+
+		key := period(backup.CreatedAt.Local())
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		keep[i] = true
+	}
+}
@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+)
+
+// backupsAt builds one backup per time, newest first as prunable expects,
+// named after the date and hour it was taken.
+func backupsAt(size int64, times ...time.Time) []*BackupInfo {
+	var backups []*BackupInfo
+	for _, t := range times {
+		backups = append(backups, &BackupInfo{Manifest: Manifest{
+			Name:      t.Format("2006-01-02T15"),
+			Size:      size,
+			CreatedAt: t,
+		}})
+	}
+	return backups
+}
+
+func day(year int, month time.Month, d, hour int) time.Time {
+	return time.Date(year, month, d, hour, 0, 0, 0, time.Local)
+}
+
+func TestPrunable(t *testing.T) {
+	tests := []struct {
+		name    string
+		backups []*BackupInfo
+		policy  config.RetentionPolicy
+		want    []string
+	}{
+		{
+			name:    "no backups",
+			backups: nil,
+			policy:  config.RetentionPolicy{KeepLast: 1},
+			want:    nil,
+		},
+		{
+			name:    "keep last",
+			backups: backupsAt(1, day(2026, 1, 4, 12), day(2026, 1, 3, 12), day(2026, 1, 2, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{KeepLast: 2},
+			want:    []string{"2026-01-02T12", "2026-01-01T12"},
+		},
+		{
+			name:    "keep daily keeps the newest of each day",
+			backups: backupsAt(1, day(2026, 1, 3, 18), day(2026, 1, 3, 6), day(2026, 1, 2, 18), day(2026, 1, 2, 6), day(2026, 1, 1, 18)),
+			policy:  config.RetentionPolicy{KeepDaily: 2},
+			want:    []string{"2026-01-03T06", "2026-01-02T06", "2026-01-01T18"},
+		},
+		{
+			name:    "keep daily counts days that have a backup",
+			backups: backupsAt(1, day(2026, 1, 10, 12), day(2026, 1, 3, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{KeepDaily: 2},
+			want:    []string{"2026-01-01T12"},
+		},
+		{
+			// 2026-01-04 is a Sunday, the last day of ISO week 1, and
+			// 2025-12-29 the Monday of that same week.
+			name:    "keep weekly uses ISO weeks",
+			backups: backupsAt(1, day(2026, 1, 5, 12), day(2026, 1, 4, 12), day(2025, 12, 29, 12), day(2025, 12, 28, 12)),
+			policy:  config.RetentionPolicy{KeepWeekly: 3},
+			want:    []string{"2025-12-29T12"},
+		},
+		{
+			// 2027-01-01 falls in ISO week 53 of 2026.
+			name:    "keep weekly across the year boundary",
+			backups: backupsAt(1, day(2027, 1, 4, 12), day(2027, 1, 1, 12), day(2026, 12, 28, 12), day(2026, 12, 27, 12)),
+			policy:  config.RetentionPolicy{KeepWeekly: 2},
+			want:    []string{"2026-12-28T12", "2026-12-27T12"},
+		},
+		{
+			name:    "keep monthly",
+			backups: backupsAt(1, day(2026, 3, 1, 12), day(2026, 2, 28, 12), day(2026, 2, 1, 12), day(2026, 1, 15, 12)),
+			policy:  config.RetentionPolicy{KeepMonthly: 2},
+			want:    []string{"2026-02-01T12", "2026-01-15T12"},
+		},
+		{
+			name:    "rules add up",
+			backups: backupsAt(1, day(2026, 2, 2, 12), day(2026, 2, 1, 12), day(2026, 1, 31, 12), day(2026, 1, 30, 12), day(2025, 12, 31, 12)),
+			policy:  config.RetentionPolicy{KeepLast: 1, KeepDaily: 2, KeepMonthly: 3},
+			want:    []string{"2026-01-30T12"},
+		},
+		{
+			name:    "newest is kept when no rule keeps it",
+			backups: backupsAt(1, day(2026, 1, 2, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{KeepMonthly: 1, MaxSize: "1B"},
+			want:    []string{"2026-01-01T12"},
+		},
+		{
+			name:    "max size alone evicts the oldest",
+			backups: backupsAt(400, day(2026, 1, 4, 12), day(2026, 1, 3, 12), day(2026, 1, 2, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{MaxSize: "1KB"},
+			want:    []string{"2026-01-02T12", "2026-01-01T12"},
+		},
+		{
+			name:    "max size only counts kept backups",
+			backups: backupsAt(400, day(2026, 1, 4, 12), day(2026, 1, 3, 12), day(2026, 1, 2, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{KeepLast: 2, MaxSize: "800B"},
+			want:    []string{"2026-01-02T12", "2026-01-01T12"},
+		},
+		{
+			name:    "max size never evicts the newest",
+			backups: backupsAt(2048, day(2026, 1, 2, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{MaxSize: "1KB"},
+			want:    []string{"2026-01-01T12"},
+		},
+		{
+			name:    "max size within the limit prunes nothing",
+			backups: backupsAt(100, day(2026, 1, 2, 12), day(2026, 1, 1, 12)),
+			policy:  config.RetentionPolicy{MaxSize: "1KB"},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, backup := range prunable(tt.backups, &tt.policy) {
+				got = append(got, backup.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("prunable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
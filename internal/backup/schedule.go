@@ -0,0 +1,69 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/environment"
+)
+
+// ScheduleInfo is the backup schedule in effect for one database: its own,
+// or that of the first environment containing it that sets one.
+type ScheduleInfo struct {
+	Database    string                 `json:"database" yaml:"database"`
+	Source      string                 `json:"source" yaml:"source"`
+	Environment string                 `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Schedule    *config.BackupSchedule `json:"schedule" yaml:"schedule"`
+}
+
+// Schedules lists the registered databases that have a backup schedule.
+func (bm *BackupManager) Schedules() ([]ScheduleInfo, error) {
+	registry, err := bm.store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load database registry: %w", err)
+	}
+
+	environments, err := environment.NewEnvironmentManager().ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(environments, func(i, j int) bool {
+		return environments[i].Name < environments[j].Name
+	})
+
+	schedules := []ScheduleInfo{}
+	for _, database := range registry.Databases {
+		if database.Schedule != nil && database.Schedule.Cron != "" {
+			schedules = append(schedules, ScheduleInfo{Database: database.Name, Source: PolicySourceDatabase, Schedule: database.Schedule})
+			continue
+		}
+
+		for _, env := range environments {
+			if _, ok := env.Databases[database.Name]; ok && env.Schedule != nil && env.Schedule.Cron != "" {
+				schedules = append(schedules, ScheduleInfo{Database: database.Name, Source: PolicySourceEnvironment, Environment: env.Name, Schedule: env.Schedule})
+				break
+			}
+		}
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Database < schedules[j].Database
+	})
+
+	return schedules, nil
+}
+
+// SetDatabaseSchedule sets the backup schedule of one database. A nil
+// schedule removes it.
+func (bm *BackupManager) SetDatabaseSchedule(dbName string, schedule *config.BackupSchedule) error {
+	return bm.store.Update(func(registry *config.DatabaseRegistry) error {
+		for i := range registry.Databases {
+			if registry.Databases[i].Name == dbName {
+				registry.Databases[i].Schedule = schedule
+				return nil
+			}
+		}
+		return fmt.Errorf("database '%s' not found", dbName)
+	})
+}
@@ -0,0 +1,174 @@
+package backup
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/db"
+)
+
+// Verification is the outcome of the last test restore of a backup,
+// recorded on its manifest.
+type Verification struct {
+	VerifiedAt time.Time         `json:"verified_at" yaml:"verified_at"`
+	Passed     bool              `json:"passed" yaml:"passed"`
+	ChecksumOK bool              `json:"checksum_ok" yaml:"checksum_ok"`
+	Restored   bool              `json:"restored" yaml:"restored"`
+	Tables     map[string]int64  `json:"tables,omitempty" yaml:"tables,omitempty"`
+	Assertions []AssertionResult `json:"assertions,omitempty" yaml:"assertions,omitempty"`
+	Error      string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type AssertionResult struct {
+	Query  string `json:"query" yaml:"query"`
+	Passed bool   `json:"passed" yaml:"passed"`
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// VerifyBackup proves a backup restores: it checks the checksum, restores
+// into a scratch instance of the recorded engine and version, counts the
+// rows of every table and runs the assertions, then removes the scratch
+// instance. The result is recorded on the manifest. An error is returned
+// only when the verification itself could not run.
+func (bm *BackupManager) VerifyBackup(backupName string, assertions []string) (*Verification, error) {
+	backup, err := bm.GetBackup(backupName)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := db.GetEngine(backup.Type)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine backup type for: %s", backup.Name)
+	}
+
+	if len(assertions) > 0 {
+		if _, ok := engine.(db.AssertingEngine); !ok {
+			return nil, fmt.Errorf("assertions are not supported for %s backups", engine.DisplayName())
+		}
+	}
+
+	verification := &Verification{VerifiedAt: time.Now()}
+
+	checksum, _, err := fileChecksum(backup.FilePath)
+	switch {
+	case err != nil:
+		verification.Error = fmt.Sprintf("failed to read backup file: %v", err)
+	case checksum != backup.SHA256:
+		verification.Error = "checksum does not match the manifest"
+	default:
+		verification.ChecksumOK = true
+	}
+
+	if verification.ChecksumOK {
+		scratch, cleanup, err := bm.scratchInstance(engine, backup)
+		if err != nil {
+			return nil, err
+		}
+
+		bm.testRestore(engine, backup, scratch, assertions, verification)
+
+		if err := cleanup(); err != nil {
+			fmt.Printf("⚠️  Failed to remove scratch instance '%s': %v\n", scratch.Name, err)
+		}
+	}
+
+	verification.Passed = verification.ChecksumOK && verification.Restored && verification.Error == ""
+	for _, assertion := range verification.Assertions {
+		verification.Passed = verification.Passed && assertion.Passed
+	}
+
+	backup.Verification = verification
+	if err := writeManifest(bm.backupDir, &backup.Manifest); err != nil {
+		return verification, err
+	}
+
+	return verification, nil
+}
+
+func (bm *BackupManager) testRestore(engine db.Engine, backup *BackupInfo, scratch *config.DatabaseConfig, assertions []string, verification *Verification) {
+	fmt.Printf("Restoring '%s' into scratch instance '%s'...\n", backup.Name, scratch.Name)
+	if err := bm.readBackup(engine, scratch, backup.FilePath, backup.Compressed, true); err != nil {
+		verification.Error = fmt.Sprintf("restore failed: %v", err)
+		return
+	}
+	verification.Restored = true
+
+	// Restoring Redis restarts the server, so wait until it answers again.
+	if err := db.WaitForEngine(engine, scratch, 60*time.Second); err != nil {
+		verification.Error = fmt.Sprintf("database did not come back after the restore: %v", err)
+		return
+	}
+
+	if verifier, ok := engine.(db.VerifyingEngine); ok {
+		tables, err := verifier.TableCounts(scratch)
+		if err != nil {
+			verification.Error = fmt.Sprintf("sanity check failed: %v", err)
+			return
+		}
+		verification.Tables = tables
+	}
+
+	if asserter, ok := engine.(db.AssertingEngine); ok {
+		for _, query := range assertions {
+			result := AssertionResult{Query: query}
+			passed, err := asserter.Assert(scratch, query)
+			if err != nil {
+				result.Error = err.Error()
+			}
+			result.Passed = passed && err == nil
+			verification.Assertions = append(verification.Assertions, result)
+		}
+	}
+}
+
+// scratchInstance creates a throwaway database to restore into: a container
+// of the backup's engine and version, or a temporary file for SQLite. It is
+// created with the source's user, so a dump that assigns ownership to that
+// user restores without errors.
+func (bm *BackupManager) scratchInstance(engine db.Engine, backup *BackupInfo) (*config.DatabaseConfig, func() error, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, nil, err
+	}
+	name := "verify-" + hex.EncodeToString(suffix)
+
+	if !engine.Containerized() {
+		dir, err := os.MkdirTemp("", "spindb-verify-*")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create scratch directory: %w", err)
+		}
+
+		scratch := &config.DatabaseConfig{
+			Name:     name,
+			Type:     engine.Name(),
+			FilePath: filepath.Join(dir, name+engine.BackupExtension()),
+		}
+		return scratch, func() error { return os.RemoveAll(dir) }, nil
+	}
+
+	manager := db.NewManager()
+
+	fmt.Printf("Creating scratch %s instance '%s'...\n", engine.DisplayName(), name)
+	err := manager.Create(engine.Name(), &db.InstanceConfig{
+		Name:       name,
+		User:       backup.User,
+		Version:    backup.Version,
+		Extensions: backup.Extensions,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create scratch instance: %w", err)
+	}
+
+	scratch, err := bm.store.Get(name, engine.Name())
+	if err != nil {
+		manager.Remove(name, true)
+		return nil, nil, err
+	}
+
+	return scratch, func() error { return manager.Remove(name, true) }, nil
+}
@@ -1,19 +1,43 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/awade12/spindb/internal/utils"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+const ProjectConfigFile = ".spindb.yaml"
+
+var (
+	defaultsOnce sync.Once
+	defaultKeys  map[string]interface{}
 )
 
 type Config struct {
 	Default DefaultConfig `yaml:"default"`
 	Docker  DockerConfig  `yaml:"docker"`
 	Storage StorageConfig `yaml:"storage"`
+	Secrets SecretsConfig `yaml:"secrets"`
+	Backup  BackupConfig  `yaml:"backup"`
 }
 
 type DefaultConfig struct {
-	Postgres PostgresDefaults `yaml:"postgres"`
-	MySQL    MySQLDefaults    `yaml:"mysql"`
+	Postgres   PostgresDefaults   `yaml:"postgres"`
+	MySQL      MySQLDefaults      `yaml:"mysql"`
+	MariaDB    MariaDBDefaults    `yaml:"mariadb"`
+	Redis      RedisDefaults      `yaml:"redis"`
+	Mongo      MongoDefaults      `yaml:"mongo"`
+	ClickHouse ClickHouseDefaults `yaml:"clickhouse"`
+	MSSQL      MSSQLDefaults      `yaml:"mssql"`
 }
 
 type PostgresDefaults struct {
@@ -28,18 +52,61 @@ type MySQLDefaults struct {
 	User    string `yaml:"user"`
 }
 
+type MariaDBDefaults struct {
+	Version string `yaml:"version"`
+	Port    int    `yaml:"port"`
+	User    string `yaml:"user"`
+}
+
+type RedisDefaults struct {
+	Version string `yaml:"version"`
+	Port    int    `yaml:"port"`
+}
+
+type MongoDefaults struct {
+	Version string `yaml:"version"`
+	Port    int    `yaml:"port"`
+	User    string `yaml:"user"`
+}
+
+type ClickHouseDefaults struct {
+	Version    string `yaml:"version"`
+	Port       int    `yaml:"port"`
+	NativePort int    `yaml:"native_port"`
+	User       string `yaml:"user"`
+}
+
+type MSSQLDefaults struct {
+	Version string `yaml:"version"`
+	Port    int    `yaml:"port"`
+	User    string `yaml:"user"`
+}
+
 type DockerConfig struct {
 	Host           string `yaml:"host"`
 	CleanupTimeout string `yaml:"cleanup_timeout"`
 }
 
+type SecretsConfig struct {
+	Backend string `yaml:"backend"`
+	KeyFile string `yaml:"key_file"`
+}
+
+// BackupConfig holds the global retention policy, used for databases whose
+// own entry and environment set none.
+type BackupConfig struct {
+	Retention RetentionPolicy `yaml:"retention"`
+	AutoPrune bool            `yaml:"auto_prune"`
+}
+
 type StorageConfig struct {
-	DataDir   string `yaml:"data_dir"`
-	BackupDir string `yaml:"backup_dir"`
+	DataDir     string `yaml:"data_dir"`
+	BackupDir   string `yaml:"backup_dir"`
+	SnapshotDir string `yaml:"snapshot_dir"`
 }
 
-func Load() *Config {
-	home, _ := os.UserHomeDir()
+func Defaults() *Config {
+	home := utils.SpinDBHome()
 
 	return &Config{
 		Default: DefaultConfig{
@@ -53,14 +120,226 @@ func Load() *Config {
 				Port:    3306,
 				User:    "root",
 			},
+			MariaDB: MariaDBDefaults{
+				Version: "11.4",
+				Port:    3306,
+				User:    "root",
+			},
+			Redis: RedisDefaults{
+				Version: "7",
+				Port:    6379,
+			},
+			Mongo: MongoDefaults{
+				Version: "7",
+				Port:    27017,
+				User:    "root",
+			},
+			ClickHouse: ClickHouseDefaults{
+				Version:    "24.8",
+				Port:       8123,
+				NativePort: 9000,
+				User:       "default",
+			},
+			MSSQL: MSSQLDefaults{
+				Version: "2022-latest",
+				Port:    1433,
+				User:    "sa",
+			},
 		},
 		Docker: DockerConfig{
 			Host:           "unix:///var/run/docker.sock",
 			CleanupTimeout: "30s",
 		},
 		Storage: StorageConfig{
-			DataDir:   filepath.Join(home, ".spindb", "data"),
-			BackupDir: filepath.Join(home, ".spindb", "backups"),
+			DataDir:     filepath.Join(home, "data"),
+			BackupDir:   filepath.Join(home, "backups"),
+			SnapshotDir: filepath.Join(home, "snapshots"),
+		},
+		Secrets: SecretsConfig{
+			Backend: "vault",
+			KeyFile: filepath.Join(home, "vault.key"),
 		},
 	}
 }
+
+// Init layers the configuration sources onto the global viper instance:
+// built-in defaults < global config file < project file < SPINDB_* env vars.
+// Flags bound with viper.BindPFlag take precedence over all of them. An
+// explicit file replaces the global file.
+func Init(cfgFile string) ([]string, error) {
+	setDefaults()
+
+	globalFile := cfgFile
+	if globalFile == "" {
+		globalFile = GlobalConfigPath()
+	}
+
+	var used []string
+	viper.SetConfigType("yaml")
+
+	for _, file := range []string{globalFile, ProjectConfigPath()} {
+		if _, err := os.Stat(file); err != nil {
+			if cfgFile != "" && file == cfgFile {
+				return nil, fmt.Errorf("config file %s not found", cfgFile)
+			}
+			continue
+		}
+
+		viper.SetConfigFile(file)
+		if err := viper.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+		used = append(used, file)
+	}
+
+	viper.SetEnvPrefix("SPINDB")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
+	return used, nil
+}
+
+// Check reports whether the layered configuration decodes. Commands call it
+// before doing anything, so a typo in a config file stops them instead of
+// quietly putting data and backups in the default directories.
+func Check() error {
+	if _, err := decode(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Load returns the effective configuration. It panics on a configuration
+// Check rejects, which commands have already refused to run with.
+func Load() *Config {
+	cfg, err := decode()
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func decode() (*Config, error) {
+	setDefaults()
+
+	cfg := Defaults()
+	if err := viper.Unmarshal(cfg, yamlTags); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func GlobalConfigPath() string {
+	return filepath.Join(utils.SpinDBHome(), "config.yaml")
+}
+
+func ProjectConfigPath() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ProjectConfigFile
+	}
+	return filepath.Join(wd, ProjectConfigFile)
+}
+
+// Keys returns every configuration key in dotted form, e.g. storage.data_dir.
+func Keys() []string {
+	setDefaults()
+
+	keys := viper.AllKeys()
+	sort.Strings(keys)
+	return keys
+}
+
+func IsKey(key string) bool {
+	return defaultValue(key) != nil
+}
+
+// SetInFile writes a single key to a config file, converting value to the
+// type of the built-in default and leaving the file's other keys untouched.
+func SetInFile(path, key, value string) error {
+	key = strings.ToLower(key)
+
+	def := defaultValue(key)
+	if def == nil {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+
+	var typed interface{} = value
+	switch def.(type) {
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", key)
+		}
+		typed = n
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		typed = b
+	}
+
+	file := viper.New()
+	file.SetConfigType("yaml")
+	file.SetConfigFile(path)
+	if _, err := os.Stat(path); err == nil {
+		if err := file.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+	}
+
+	file.Set(key, typed)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := file.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func yamlTags(dc *mapstructure.DecoderConfig) {
+	dc.TagName = "yaml"
+}
+
+func setDefaults() {
+	defaultsOnce.Do(func() {
+		defaultKeys = flatten("", reflect.ValueOf(*Defaults()))
+		for key, value := range defaultKeys {
+			viper.SetDefault(key, value)
+		}
+	})
+}
+
+func defaultValue(key string) interface{} {
+	setDefaults()
+	return defaultKeys[strings.ToLower(key)]
+}
+
+func flatten(prefix string, v reflect.Value) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			for k, value := range flatten(key, v.Field(i)) {
+				values[k] = value
+			}
+			continue
+		}
+
+		values[key] = v.Field(i).Interface()
+	}
+
+	return values
+}
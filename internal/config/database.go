@@ -3,15 +3,31 @@ package config
 import "time"
 
 type DatabaseConfig struct {
-	Name        string    `yaml:"name"`
-	Type        string    `yaml:"type"`
-	Version     string    `yaml:"version,omitempty"`
-	Port        int       `yaml:"port,omitempty"`
-	User        string    `yaml:"user,omitempty"`
-	Password    string    `yaml:"password,omitempty"`
-	FilePath    string    `yaml:"file_path,omitempty"`
-	Public      bool      `yaml:"public,omitempty"`
-	ContainerID string    `yaml:"container_id,omitempty"`
-	Created     time.Time `yaml:"created"`
-	LastUsed    time.Time `yaml:"last_used,omitempty"`
+	Name        string           `json:"name" yaml:"name"`
+	Type        string           `json:"type" yaml:"type"`
+	Database    string           `json:"database,omitempty" yaml:"database,omitempty"`
+	Parent      string           `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Version     string           `json:"version,omitempty" yaml:"version,omitempty"`
+	Port        int              `json:"port,omitempty" yaml:"port,omitempty"`
+	Ports       map[string]int   `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Extensions  []string         `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	User        string           `json:"user,omitempty" yaml:"user,omitempty"`
+	Password    string           `json:"-" yaml:"-"`
+	PasswordRef string           `json:"password_ref,omitempty" yaml:"password_ref,omitempty"`
+	FilePath    string           `json:"file_path,omitempty" yaml:"file_path,omitempty"`
+	Public      bool             `json:"public,omitempty" yaml:"public,omitempty"`
+	ContainerID string           `json:"container_id,omitempty" yaml:"container_id,omitempty"`
+	Retention   *RetentionPolicy `json:"retention,omitempty" yaml:"retention,omitempty"`
+	Schedule    *BackupSchedule  `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	Created     time.Time        `json:"created" yaml:"created"`
+	LastUsed    time.Time        `json:"last_used,omitempty" yaml:"last_used,omitempty"`
+}
+
+// DatabaseName is the logical database inside the server. It only differs
+// from Name for branches, which inherit the parent's data directory.
+func (d *DatabaseConfig) DatabaseName() string {
+	if d.Database != "" {
+		return d.Database
+	}
+	return d.Name
 }
@@ -0,0 +1,379 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/awade12/spindb/internal/secrets"
+	"github.com/awade12/spindb/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the registry, environment and template
+// files written by this build. Bump it together with a new entry in the
+// matching migration chain whenever a stored format changes.
+const SchemaVersion = 2
+
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]interface{}, ctx *MigrationContext) error
+}
+
+// MigrationContext carries what a migration may touch besides the document
+// itself. Side effects are skipped on a dry run.
+type MigrationContext struct {
+	DryRun bool
+	// KeepExisting leaves secrets that are already stored untouched, for
+	// documents that may hold older copies of them.
+	KeepExisting bool
+}
+
+func (ctx *MigrationContext) StoreSecret(key, value string) error {
+	if ctx.DryRun {
+		return nil
+	}
+	if ctx.KeepExisting && hasSecret(key) {
+		return nil
+	}
+	return storeSecret(key, value)
+}
+
+type MigrationResult struct {
+	Path  string
+	From  int
+	To    int
+	Steps []string
+}
+
+// errDamaged marks a state file that is empty or does not parse. Such a file
+// is the leftover of an interrupted write, never an unversioned (v0) file.
+var errDamaged = errors.New("file is damaged")
+
+var baseline = Migration{
+	From:        0,
+	Description: "add schema_version (files written before versioning)",
+	Apply:       func(doc map[string]interface{}, ctx *MigrationContext) error { return nil },
+}
+
+var (
+	registryMigrations = []Migration{
+		baseline,
+		{
+			From:        1,
+			Description: "move plaintext passwords into the secrets store",
+			Apply:       moveRegistryPasswords,
+		},
+	}
+	environmentMigrations = []Migration{
+		baseline,
+		{
+			From:        1,
+			Description: "replace embedded passwords with secrets store references",
+			Apply:       dropEnvironmentPasswords,
+		},
+	}
+	templateMigrations = []Migration{
+		baseline,
+		{
+			From:        1,
+			Description: "move the template password into the secrets store",
+			Apply:       moveTemplatePassword,
+		},
+	}
+)
+
+func moveRegistryPasswords(doc map[string]interface{}, ctx *MigrationContext) error {
+	databases, _ := doc["databases"].([]interface{})
+	for _, item := range databases {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		password, _ := entry["password"].(string)
+		delete(entry, "password")
+		if password == "" {
+			continue
+		}
+
+		dbType, _ := entry["type"].(string)
+		name, _ := entry["name"].(string)
+		key := secrets.DatabaseKey(dbType, name)
+		if err := ctx.StoreSecret(key, password); err != nil {
+			return err
+		}
+		entry["password_ref"] = key
+	}
+	return nil
+}
+
+// Environment files hold copies of registry entries, whose passwords the
+// registry migration has already moved, so only the reference is kept.
+func dropEnvironmentPasswords(doc map[string]interface{}, ctx *MigrationContext) error {
+	databases, _ := doc["databases"].(map[string]interface{})
+	for _, item := range databases {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		password, _ := entry["password"].(string)
+		delete(entry, "password")
+		if password == "" {
+			continue
+		}
+
+		dbType, _ := entry["type"].(string)
+		name, _ := entry["name"].(string)
+		entry["password_ref"] = secrets.DatabaseKey(dbType, name)
+	}
+	return nil
+}
+
+func moveTemplatePassword(doc map[string]interface{}, ctx *MigrationContext) error {
+	cfg, _ := doc["config"].(map[string]interface{})
+	password, _ := cfg["password"].(string)
+	if password == "" {
+		return nil
+	}
+
+	name, _ := doc["name"].(string)
+	key := secrets.TemplateKey(name)
+	if err := ctx.StoreSecret(key, password); err != nil {
+		return err
+	}
+
+	delete(cfg, "password")
+	cfg["password_ref"] = key
+	return nil
+}
+
+// MigrateState upgrades the registry, environment files and templates under
+// home to SchemaVersion. With dryRun nothing is written; otherwise each
+// changed file is first copied to <file>.v<old>.bak, without its passwords.
+func MigrateState(home string, dryRun bool) ([]*MigrationResult, error) {
+	groups := []struct {
+		lockPath   string
+		files      []string
+		migrations []Migration
+		// fallback is set when loading falls back to <file>.bak, so a
+		// damaged file is left for that fallback instead of failing.
+		fallback bool
+	}{
+		{filepath.Join(home, "databases.lock"), []string{filepath.Join(home, "databases.yaml")}, registryMigrations, true},
+		{filepath.Join(home, "environments", ".lock"), globYAML(filepath.Join(home, "environments")), environmentMigrations, false},
+		{filepath.Join(home, "templates", ".lock"), globYAML(filepath.Join(home, "templates")), templateMigrations, false},
+	}
+
+	var results []*MigrationResult
+	for _, group := range groups {
+		groupResults, err := migrateGroup(group.lockPath, group.files, group.migrations, group.fallback, dryRun)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, groupResults...)
+	}
+
+	return results, nil
+}
+
+func migrateGroup(lockPath string, files []string, migrations []Migration, fallback, dryRun bool) ([]*MigrationResult, error) {
+	var existing []string
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			existing = append(existing, file)
+		}
+	}
+	if len(existing) == 0 {
+		return nil, nil
+	}
+
+	if !dryRun {
+		lock, err := utils.LockFile(lockPath)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Unlock()
+	}
+
+	var results []*MigrationResult
+	for _, file := range existing {
+		result, err := migrateFile(file, migrations, &MigrationContext{DryRun: dryRun})
+		switch {
+		case err != nil && fallback && errors.Is(err, errDamaged):
+			fmt.Fprintf(os.Stderr, "⚠️  %v; not migrating it, the last good copy in %s.bak is used instead\n", err, file)
+		case err != nil:
+			return results, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+
+		if fallback {
+			backupResult, err := migrateLastGoodCopy(file+".bak", migrations, dryRun)
+			if err != nil {
+				return results, err
+			}
+			if backupResult != nil {
+				results = append(results, backupResult)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func migrateFile(path string, migrations []Migration, ctx *MigrationContext) (*MigrationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	migrated, result, err := migrateDocument(data, migrations, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	result.Path = path
+
+	if ctx.DryRun {
+		return result, nil
+	}
+
+	backup, err := blankPasswords(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", path, result.From)
+	if err := utils.WriteFileAtomic(backupPath, backup, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	if err := utils.WriteFileAtomic(path, migrated, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return result, nil
+}
+
+// blankPasswords returns the document with the value of every password key
+// emptied, for the backup kept of a file before its migration. The secrets
+// store holds the passwords by then, and a plaintext copy would outlive it.
+func blankPasswords(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	blankPasswordNodes(&doc)
+	return yaml.Marshal(&doc)
+}
+
+func blankPasswordNodes(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "password" && value.Kind == yaml.ScalarNode {
+				value.Value = ""
+				value.Tag = "!!str"
+				value.Style = 0
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		blankPasswordNodes(child)
+	}
+}
+
+// migrateLastGoodCopy upgrades <file>.bak as a document of its own, so the
+// fallback does not keep plaintext passwords. A damaged copy is left alone,
+// since loading never uses it, and secrets it holds never replace the ones
+// already stored from the main file.
+func migrateLastGoodCopy(path string, migrations []Migration, dryRun bool) (*MigrationResult, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil
+	}
+
+	result, err := migrateFile(path, migrations, &MigrationContext{DryRun: dryRun, KeepExisting: true})
+	if err != nil && errors.Is(err, errDamaged) {
+		return nil, nil
+	}
+
+	return result, err
+}
+
+// migrateDocument runs the migrations a document still needs and returns
+// the upgraded YAML. The result is nil when the document is already current.
+func migrateDocument(data []byte, migrations []Migration, ctx *MigrationContext) ([]byte, *MigrationResult, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil, fmt.Errorf("%w: file is empty", errDamaged)
+	}
+
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", errDamaged, err)
+	}
+
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if version > SchemaVersion {
+		return nil, nil, fmt.Errorf("schema version %d is newer than this spindb supports (%d), please upgrade spindb", version, SchemaVersion)
+	}
+
+	if version == SchemaVersion {
+		return nil, nil, nil
+	}
+
+	result := &MigrationResult{From: version, To: SchemaVersion}
+	for _, migration := range migrations {
+		if migration.From < version {
+			continue
+		}
+
+		if err := migration.Apply(doc, ctx); err != nil {
+			return nil, nil, fmt.Errorf("v%d to v%d: %w", migration.From, migration.From+1, err)
+		}
+
+		doc["schema_version"] = migration.From + 1
+		result.Steps = append(result.Steps, migration.Description)
+	}
+
+	migrated, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return migrated, result, nil
+}
+
+func documentVersion(doc map[string]interface{}) (int, error) {
+	raw, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+
+	version, ok := raw.(int)
+	if !ok {
+		return 0, fmt.Errorf("invalid schema_version %v", raw)
+	}
+
+	return version, nil
+}
+
+func globYAML(dir string) []string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	sort.Strings(files)
+	return files
+}
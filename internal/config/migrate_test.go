@@ -0,0 +1,324 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/awade12/spindb/internal/secrets"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	registryV0 = `databases:
+  - name: app
+    type: postgres
+    user: postgres
+    password: app-password
+    port: 5432
+  - name: cache
+    type: redis
+    port: 6379
+`
+	registryV1 = "schema_version: 1\n" + registryV0
+
+	environmentV1 = `schema_version: 1
+name: dev
+databases:
+  app:
+    name: app
+    type: postgres
+    password: app-password
+`
+	templateV0 = `name: web
+type: postgres
+config:
+  user: web
+  password: template-password
+`
+)
+
+func writeState(t *testing.T, home, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(home, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readVersion(t *testing.T, path string) int {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("parsing %s: %v", path, err)
+	}
+
+	version, err := documentVersion(doc)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return version
+}
+
+func TestMigrateState(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		wantFrom    int
+		wantSteps   int
+		wantSecrets map[string]string
+		wantRefs    []string
+	}{
+		{
+			name:        "registry v0",
+			file:        "databases.yaml",
+			content:     registryV0,
+			wantFrom:    0,
+			wantSteps:   2,
+			wantSecrets: map[string]string{secrets.DatabaseKey("postgres", "app"): "app-password"},
+			wantRefs:    []string{secrets.DatabaseKey("postgres", "app")},
+		},
+		{
+			name:        "registry v1",
+			file:        "databases.yaml",
+			content:     registryV1,
+			wantFrom:    1,
+			wantSteps:   1,
+			wantSecrets: map[string]string{secrets.DatabaseKey("postgres", "app"): "app-password"},
+			wantRefs:    []string{secrets.DatabaseKey("postgres", "app")},
+		},
+		{
+			// Environments only get the reference, the registry stores the
+			// password.
+			name:      "environment v1",
+			file:      "environments/dev.yaml",
+			content:   environmentV1,
+			wantFrom:  1,
+			wantSteps: 1,
+			wantRefs:  []string{secrets.DatabaseKey("postgres", "app")},
+		},
+		{
+			name:        "template v0",
+			file:        "templates/web.yaml",
+			content:     templateV0,
+			wantFrom:    0,
+			wantSteps:   2,
+			wantSecrets: map[string]string{secrets.TemplateKey("web"): "template-password"},
+			wantRefs:    []string{secrets.TemplateKey("web")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := useTestHome(t)
+			path := writeState(t, home, tt.file, tt.content)
+
+			results, err := MigrateState(home, false)
+			if err != nil {
+				t.Fatalf("MigrateState: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+
+			result := results[0]
+			if result.Path != path || result.From != tt.wantFrom || result.To != SchemaVersion || len(result.Steps) != tt.wantSteps {
+				t.Errorf("result = %+v, want %s from v%d to v%d in %d steps", result, path, tt.wantFrom, SchemaVersion, tt.wantSteps)
+			}
+
+			if version := readVersion(t, path); version != SchemaVersion {
+				t.Errorf("migrated file has schema version %d, want %d", version, SchemaVersion)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ref := range tt.wantRefs {
+				if !strings.Contains(string(data), "password_ref: "+ref) {
+					t.Errorf("migrated file does not reference %s:\n%s", ref, data)
+				}
+			}
+
+			for key, want := range tt.wantSecrets {
+				if got, err := secretsStore.Get(key); err != nil || got != want {
+					t.Errorf("secret %s = %q, %v, want %q", key, got, err, want)
+				}
+			}
+
+			backupPath := fmt.Sprintf("%s.v%d.bak", path, tt.wantFrom)
+			if version := readVersion(t, backupPath); version != tt.wantFrom {
+				t.Errorf("backup %s has schema version %d, want %d", backupPath, version, tt.wantFrom)
+			}
+
+			// A second run finds nothing left to do.
+			if results, err := MigrateState(home, false); err != nil || len(results) != 0 {
+				t.Errorf("second MigrateState = %v, %v, want no results", results, err)
+			}
+		})
+	}
+}
+
+func TestMigrateStateDryRun(t *testing.T) {
+	home := useTestHome(t)
+	registry := writeState(t, home, "databases.yaml", registryV1)
+	template := writeState(t, home, "templates/web.yaml", templateV0)
+
+	results, err := MigrateState(home, true)
+	if err != nil {
+		t.Fatalf("MigrateState: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	for path, want := range map[string]string{registry: registryV1, template: templateV0} {
+		if data, _ := os.ReadFile(path); string(data) != want {
+			t.Errorf("dry run changed %s:\n%s", path, data)
+		}
+		if matches, _ := filepath.Glob(path + ".v*.bak"); len(matches) > 0 {
+			t.Errorf("dry run wrote %v", matches)
+		}
+	}
+
+	if _, err := secretsStore.Get(secrets.DatabaseKey("postgres", "app")); err != secrets.ErrNotFound {
+		t.Errorf("dry run stored a secret: %v", err)
+	}
+}
+
+func TestMigrateStateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"newer registry", "databases.yaml", "schema_version: 99\ndatabases: []\n"},
+		{"invalid version", "databases.yaml", "schema_version: two\ndatabases: []\n"},
+		{"empty environment", "environments/dev.yaml", ""},
+		{"damaged template", "templates/web.yaml", "name: [web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := useTestHome(t)
+			path := writeState(t, home, tt.file, tt.content)
+
+			if _, err := MigrateState(home, false); err == nil {
+				t.Fatal("MigrateState succeeded, want an error")
+			}
+			if data, _ := os.ReadFile(path); string(data) != tt.content {
+				t.Errorf("failed migration changed %s:\n%s", path, data)
+			}
+		})
+	}
+}
+
+func TestMigrateStateLastGoodCopy(t *testing.T) {
+	home := useTestHome(t)
+	registry := writeState(t, home, "databases.yaml", registryV1)
+	writeState(t, home, "databases.yaml.bak", strings.Replace(registryV1, "app-password", "old-password", 1))
+
+	results, err := MigrateState(home, false)
+	if err != nil {
+		t.Fatalf("MigrateState: %v", err)
+	}
+	if len(results) != 2 || results[0].Path != registry || results[1].Path != registry+".bak" {
+		t.Fatalf("results = %+v, want the registry and its last good copy", results)
+	}
+
+	if version := readVersion(t, registry+".bak"); version != SchemaVersion {
+		t.Errorf("last good copy has schema version %d, want %d", version, SchemaVersion)
+	}
+
+	// The older password in the last good copy must not replace the current one.
+	key := secrets.DatabaseKey("postgres", "app")
+	if got, err := secretsStore.Get(key); err != nil || got != "app-password" {
+		t.Errorf("secret %s = %q, %v, want app-password", key, got, err)
+	}
+}
+
+func TestMigrateStateDamagedRegistry(t *testing.T) {
+	for _, damaged := range []string{"", "databases: [app"} {
+		t.Run(strings.TrimSpace("damaged "+damaged), func(t *testing.T) {
+			home := useTestHome(t)
+			registry := writeState(t, home, "databases.yaml", damaged)
+			writeState(t, home, "databases.yaml.bak", registryV0)
+
+			results, err := MigrateState(home, false)
+			if err != nil {
+				t.Fatalf("MigrateState: %v", err)
+			}
+			if len(results) != 1 || results[0].Path != registry+".bak" || results[0].From != 0 {
+				t.Fatalf("results = %+v, want only the last good copy from v0", results)
+			}
+
+			if data, _ := os.ReadFile(registry); string(data) != damaged {
+				t.Errorf("damaged registry was rewritten:\n%s", data)
+			}
+
+			loaded, err := NewDatabaseStore().Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(loaded.Databases) != 2 || loaded.Databases[0].Password != "app-password" {
+				t.Errorf("Load = %+v, want the migrated last good copy", loaded.Databases)
+			}
+		})
+	}
+}
+
+func TestMigrateStateLeavesNoPlaintextPasswords(t *testing.T) {
+	home := useTestHome(t)
+	writeState(t, home, "databases.yaml", registryV0)
+	writeState(t, home, "databases.yaml.bak", strings.Replace(registryV1, "app-password", "old-password", 1))
+	writeState(t, home, "environments/dev.yaml", environmentV1)
+	writeState(t, home, "templates/web.yaml", templateV0)
+
+	if _, err := MigrateState(home, false); err != nil {
+		t.Fatalf("MigrateState: %v", err)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(home, "*", "*.v*.bak"))
+	rootBackups, _ := filepath.Glob(filepath.Join(home, "*.v*.bak"))
+	if len(backups)+len(rootBackups) != 4 {
+		t.Errorf("got backups %v %v, want one per migrated file", rootBackups, backups)
+	}
+
+	err := filepath.WalkDir(home, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, password := range []string{"app-password", "old-password", "template-password"} {
+			if strings.Contains(string(data), password) {
+				t.Errorf("%s still holds %s", path, password)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := secretsStore.Get(secrets.TemplateKey("web")); err != nil || got != "template-password" {
+		t.Errorf("template secret = %q, %v, want template-password", got, err)
+	}
+}
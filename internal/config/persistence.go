@@ -1,10 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/awade12/spindb/internal/secrets"
+	"github.com/awade12/spindb/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -13,12 +16,12 @@ type DatabaseStore struct {
 }
 
 type DatabaseRegistry struct {
-	Databases []DatabaseConfig `yaml:"databases"`
+	SchemaVersion int              `yaml:"schema_version"`
+	Databases     []DatabaseConfig `yaml:"databases"`
 }
 
 func NewDatabaseStore() *DatabaseStore {
-	home, _ := os.UserHomeDir()
-	configDir := filepath.Join(home, ".spindb")
+	configDir := utils.SpinDBHome()
 
 	return &DatabaseStore{
 		configDir: configDir,
@@ -29,49 +32,115 @@ func (ds *DatabaseStore) ensureConfigDir() error {
 	return os.MkdirAll(ds.configDir, 0755)
 }
 
+// Save stores the password in the secrets backend and records only its
+// reference in the registry.
 func (ds *DatabaseStore) Save(dbConfig *DatabaseConfig) error {
+	if dbConfig.Password != "" {
+		key := secrets.DatabaseKey(dbConfig.Type, dbConfig.Name)
+		if err := storeSecret(key, dbConfig.Password); err != nil {
+			return err
+		}
+		dbConfig.PasswordRef = key
+	}
+
+	return ds.Update(func(registry *DatabaseRegistry) error {
+		for i, existing := range registry.Databases {
+			if existing.Name == dbConfig.Name && existing.Type == dbConfig.Type {
+				registry.Databases[i] = *dbConfig
+				return nil
+			}
+		}
+
+		registry.Databases = append(registry.Databases, *dbConfig)
+		return nil
+	})
+}
+
+// Update applies fn to the registry while holding the registry lock, so
+// concurrent spindb processes cannot interleave their load and save.
+func (ds *DatabaseStore) Update(fn func(registry *DatabaseRegistry) error) error {
 	if err := ds.ensureConfigDir(); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	lock, err := utils.LockFile(filepath.Join(ds.configDir, "databases.lock"))
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	registry, err := ds.Load()
 	if err != nil {
-		registry = &DatabaseRegistry{Databases: []DatabaseConfig{}}
+		return err
 	}
 
-	found := false
-	for i, existing := range registry.Databases {
-		if existing.Name == dbConfig.Name && existing.Type == dbConfig.Type {
-			registry.Databases[i] = *dbConfig
-			found = true
-			break
-		}
+	// Fields dropped by a pending migration (such as plaintext passwords)
+	// would be lost by writing the registry back in the current format.
+	if registry.SchemaVersion < SchemaVersion {
+		return fmt.Errorf("registry is at schema version %d, run 'spindb migrate-state' before changing it", registry.SchemaVersion)
 	}
 
-	if !found {
-		registry.Databases = append(registry.Databases, *dbConfig)
+	if err := fn(registry); err != nil {
+		return err
 	}
 
 	return ds.saveRegistry(registry)
 }
 
+// Load reads the registry, falling back to the last good copy in
+// databases.yaml.bak when the main file is damaged.
 func (ds *DatabaseStore) Load() (*DatabaseRegistry, error) {
-	registryPath := filepath.Join(ds.configDir, "databases.yaml")
+	registryPath := ds.registryPath()
 
 	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
-		return &DatabaseRegistry{Databases: []DatabaseConfig{}}, nil
+		return &DatabaseRegistry{SchemaVersion: SchemaVersion, Databases: []DatabaseConfig{}}, nil
 	}
 
-	data, err := os.ReadFile(registryPath)
+	registry, err := readRegistry(registryPath)
+	if err != nil {
+		backup, bakErr := readRegistry(registryPath + ".bak")
+		if bakErr != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(os.Stderr, "⚠️  %v; using last good copy from %s.bak\n", err, registryPath)
+		registry = backup
+	}
+
+	for i := range registry.Databases {
+		if ref := registry.Databases[i].PasswordRef; ref != "" {
+			registry.Databases[i].Password = resolveSecret(ref)
+		}
+	}
+
+	return registry, nil
+}
+
+func readRegistry(path string) (*DatabaseRegistry, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read registry file: %w", err)
 	}
 
+	return parseRegistry(data)
+}
+
+func parseRegistry(data []byte) (*DatabaseRegistry, error) {
+	// Every saved registry has at least the databases key, so an empty file
+	// can only be the result of an interrupted write.
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("failed to parse registry file: file is empty")
+	}
+
 	var registry DatabaseRegistry
 	if err := yaml.Unmarshal(data, &registry); err != nil {
 		return nil, fmt.Errorf("failed to parse registry file: %w", err)
 	}
 
+	if registry.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("registry schema version %d is newer than this spindb supports (%d), please upgrade spindb", registry.SchemaVersion, SchemaVersion)
+	}
+
 	return &registry, nil
 }
 
@@ -90,29 +159,53 @@ func (ds *DatabaseStore) Get(name, dbType string) (*DatabaseConfig, error) {
 	return nil, fmt.Errorf("database %s of type %s not found", name, dbType)
 }
 
+// Delete removes a database from the registry together with its password.
 func (ds *DatabaseStore) Delete(name, dbType string) error {
-	registry, err := ds.Load()
+	passwordRef, err := ds.unregister(name, dbType)
 	if err != nil {
 		return err
 	}
 
-	var newDatabases []DatabaseConfig
-	found := false
+	if passwordRef != "" {
+		deleteSecret(passwordRef)
+	}
 
-	for _, db := range registry.Databases {
-		if db.Name == name && db.Type == dbType {
-			found = true
-			continue
+	return nil
+}
+
+// Unregister removes a database from the registry but keeps its password,
+// for a data directory that is kept and initialised with it. See
+// KeptPassword.
+func (ds *DatabaseStore) Unregister(name, dbType string) error {
+	_, err := ds.unregister(name, dbType)
+	return err
+}
+
+func (ds *DatabaseStore) unregister(name, dbType string) (string, error) {
+	var passwordRef string
+
+	err := ds.Update(func(registry *DatabaseRegistry) error {
+		var newDatabases []DatabaseConfig
+		found := false
+
+		for _, db := range registry.Databases {
+			if db.Name == name && db.Type == dbType {
+				found = true
+				passwordRef = db.PasswordRef
+				continue
+			}
+			newDatabases = append(newDatabases, db)
 		}
-		newDatabases = append(newDatabases, db)
-	}
 
-	if !found {
-		return fmt.Errorf("database %s of type %s not found", name, dbType)
-	}
+		if !found {
+			return fmt.Errorf("database %s of type %s not found", name, dbType)
+		}
 
-	registry.Databases = newDatabases
-	return ds.saveRegistry(registry)
+		registry.Databases = newDatabases
+		return nil
+	})
+
+	return passwordRef, err
 }
 
 func (ds *DatabaseStore) List(dbType string) ([]DatabaseConfig, error) {
@@ -135,15 +228,29 @@ func (ds *DatabaseStore) List(dbType string) ([]DatabaseConfig, error) {
 	return filtered, nil
 }
 
+func (ds *DatabaseStore) registryPath() string {
+	return filepath.Join(ds.configDir, "databases.yaml")
+}
+
 func (ds *DatabaseStore) saveRegistry(registry *DatabaseRegistry) error {
-	registryPath := filepath.Join(ds.configDir, "databases.yaml")
+	registryPath := ds.registryPath()
 
+	registry.SchemaVersion = SchemaVersion
 	data, err := yaml.Marshal(registry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal registry: %w", err)
 	}
 
-	if err := os.WriteFile(registryPath, data, 0644); err != nil {
+	// Only a registry that still parses is worth keeping as the fallback.
+	if current, err := os.ReadFile(registryPath); err == nil {
+		if _, err := parseRegistry(current); err == nil {
+			if err := utils.WriteFileAtomic(registryPath+".bak", current, 0600); err != nil {
+				return fmt.Errorf("failed to back up registry file: %w", err)
+			}
+		}
+	}
+
+	if err := utils.WriteFileAtomic(registryPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write registry file: %w", err)
 	}
 
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/awade12/spindb/internal/secrets"
+)
+
+func newTestStore(t *testing.T) *DatabaseStore {
+	t.Helper()
+	useTestHome(t)
+	return NewDatabaseStore()
+}
+
+// useTestHome points SPINDB_HOME at a fresh directory with a key file vault
+// of its own, replacing the process-wide secrets store for the test.
+func useTestHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("SPINDB_HOME", home)
+	t.Setenv(secrets.PassphraseEnv, "")
+
+	store, err := secrets.Open("vault", secrets.Options{Home: home})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secretsMu.Lock()
+	secretsStore = store
+	secretsMu.Unlock()
+	t.Cleanup(func() {
+		secretsMu.Lock()
+		secretsStore = nil
+		secretsMu.Unlock()
+	})
+
+	return home
+}
+
+func TestDatabaseStoreConcurrentSave(t *testing.T) {
+	store := newTestStore(t)
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Each writer gets its own store, like separate spindb processes.
+			errs <- NewDatabaseStore().Save(&DatabaseConfig{
+				Name: fmt.Sprintf("db-%d", i),
+				Type: "postgres",
+				Port: 5432 + i,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	databases, err := store.List("")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(databases) != writers {
+		t.Fatalf("registry has %d databases, want %d", len(databases), writers)
+	}
+
+	seen := make(map[string]bool)
+	for _, database := range databases {
+		seen[database.Name] = true
+	}
+	for i := 0; i < writers; i++ {
+		if name := fmt.Sprintf("db-%d", i); !seen[name] {
+			t.Errorf("database %s was lost", name)
+		}
+	}
+}
+
+func TestDatabaseStoreLoadFallsBackToBackup(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		damaged string
+	}{
+		{"empty", ""},
+		{"whitespace", "\n  \n"},
+		{"truncated", "schema_version: 2\ndatabases:\n  - name: [a"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t)
+
+			// The second save keeps the first registry as databases.yaml.bak.
+			for _, name := range []string{"first", "second"} {
+				if err := store.Save(&DatabaseConfig{Name: name, Type: "redis"}); err != nil {
+					t.Fatalf("Save(%s): %v", name, err)
+				}
+			}
+
+			if err := os.WriteFile(store.registryPath(), []byte(tt.damaged), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			registry, err := store.Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(registry.Databases) != 1 || registry.Databases[0].Name != "first" {
+				t.Fatalf("Load = %+v, want the last good copy with only 'first'", registry.Databases)
+			}
+
+			// A damaged registry must not replace the last good copy.
+			if err := store.Save(&DatabaseConfig{Name: "third", Type: "redis"}); err != nil {
+				t.Fatalf("Save(third): %v", err)
+			}
+			backup, err := readRegistry(store.registryPath() + ".bak")
+			if err != nil {
+				t.Fatalf("reading backup: %v", err)
+			}
+			if len(backup.Databases) != 1 || backup.Databases[0].Name != "first" {
+				t.Errorf("backup = %+v, want only 'first'", backup.Databases)
+			}
+		})
+	}
+}
+
+func TestDatabaseStoreLoadWithoutBackup(t *testing.T) {
+	store := newTestStore(t)
+
+	if err := os.MkdirAll(filepath.Dir(store.registryPath()), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.registryPath(), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Load(); err == nil {
+		t.Fatal("Load of an empty registry without a backup succeeded, want an error")
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RetentionPolicy decides which backups of a database 'backup prune' keeps.
+// Each keep rule adds backups to the kept set and a zero rule is off, so a
+// policy with no rules keeps everything. MaxSize then drops the oldest kept
+// backups until the database's backups fit, always sparing the newest.
+type RetentionPolicy struct {
+	KeepLast    int    `json:"keep_last,omitempty" yaml:"keep_last,omitempty"`
+	KeepDaily   int    `json:"keep_daily,omitempty" yaml:"keep_daily,omitempty"`
+	KeepWeekly  int    `json:"keep_weekly,omitempty" yaml:"keep_weekly,omitempty"`
+	KeepMonthly int    `json:"keep_monthly,omitempty" yaml:"keep_monthly,omitempty"`
+	MaxSize     string `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+}
+
+func (p *RetentionPolicy) IsZero() bool {
+	return p == nil || *p == RetentionPolicy{}
+}
+
+// KeepsAll reports whether no keep rule is set, in which case only MaxSize
+// can prune anything.
+func (p *RetentionPolicy) KeepsAll() bool {
+	return p.KeepLast == 0 && p.KeepDaily == 0 && p.KeepWeekly == 0 && p.KeepMonthly == 0
+}
+
+func (p *RetentionPolicy) Validate() error {
+	if p.KeepLast < 0 || p.KeepDaily < 0 || p.KeepWeekly < 0 || p.KeepMonthly < 0 {
+		return fmt.Errorf("retention counts cannot be negative")
+	}
+	if _, err := p.MaxSizeBytes(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// MaxSizeBytes parses MaxSize, e.g. 500MB or 10GB. Zero means no limit.
+func (p *RetentionPolicy) MaxSizeBytes() (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(p.MaxSize))
+	if value == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+	} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid max size '%s', use a size such as 500MB or 10GB", p.MaxSize)
+	}
+
+	return int64(n * float64(multiplier)), nil
+}
+
+func (p *RetentionPolicy) String() string {
+	if p.IsZero() {
+		return "keep all"
+	}
+
+	var rules []string
+	for _, rule := range []struct {
+		label string
+		count int
+	}{
+		{"last", p.KeepLast}, {"daily", p.KeepDaily}, {"weekly", p.KeepWeekly}, {"monthly", p.KeepMonthly},
+	} {
+		if rule.count > 0 {
+			rules = append(rules, fmt.Sprintf("%s %d", rule.label, rule.count))
+		}
+	}
+	if p.MaxSize != "" {
+		rules = append(rules, "max "+p.MaxSize)
+	}
+
+	return strings.Join(rules, ", ")
+}
@@ -0,0 +1,8 @@
+package config
+
+// BackupSchedule makes 'spindb agent' back a database up whenever Cron
+// matches. Cron is a five-field cron expression or a macro such as @daily.
+type BackupSchedule struct {
+	Cron     string `json:"cron" yaml:"cron"`
+	Compress bool   `json:"compress,omitempty" yaml:"compress,omitempty"`
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/awade12/spindb/internal/secrets"
+	"github.com/awade12/spindb/internal/utils"
+)
+
+var (
+	secretsMu      sync.Mutex
+	secretsStore   secrets.Store
+	warnSecretOnce sync.Once
+)
+
+// OpenSecrets returns the configured secrets backend, opening it on first
+// use so commands that never touch a password never unlock the vault. The
+// store stays open for the process; the vault re-reads its file whenever
+// another process changed it.
+func OpenSecrets() (secrets.Store, error) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	if secretsStore != nil {
+		return secretsStore, nil
+	}
+
+	cfg := Load()
+	store, err := secrets.Open(cfg.Secrets.Backend, secrets.Options{
+		Home:    utils.SpinDBHome(),
+		KeyFile: cfg.Secrets.KeyFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open secrets store: %w", err)
+	}
+
+	secretsStore = store
+	return store, nil
+}
+
+// resolveSecret looks up a stored reference. Failures are reported but not
+// fatal so that listing and managing databases keeps working with a locked
+// vault; only commands that actually need the password will fail later.
+func resolveSecret(ref string) string {
+	store, err := OpenSecrets()
+	if err == nil {
+		value, getErr := store.Get(ref)
+		if getErr == nil {
+			return value
+		}
+		err = getErr
+	}
+
+	warnSecretOnce.Do(func() {
+		fmt.Fprintf(os.Stderr, "⚠️  Could not read credentials (%s): %v\n", ref, err)
+	})
+	return ""
+}
+
+func storeSecret(key, value string) error {
+	store, err := OpenSecrets()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Set(key, value); err != nil {
+		return fmt.Errorf("failed to store credentials: %w", err)
+	}
+
+	return nil
+}
+
+// KeptPassword returns the password left in the secrets store for a
+// database that was unregistered with its data kept.
+func KeptPassword(dbType, name string) (string, bool) {
+	store, err := OpenSecrets()
+	if err != nil {
+		return "", false
+	}
+
+	password, err := store.Get(secrets.DatabaseKey(dbType, name))
+	if err != nil || password == "" {
+		return "", false
+	}
+	return password, true
+}
+
+func hasSecret(key string) bool {
+	store, err := OpenSecrets()
+	if err != nil {
+		return false
+	}
+
+	_, err = store.Get(key)
+	return err == nil
+}
+
+func deleteSecret(key string) {
+	if store, err := OpenSecrets(); err == nil {
+		store.Delete(key)
+	}
+}
@@ -7,17 +7,20 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/awade12/spindb/internal/secrets"
+	"github.com/awade12/spindb/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
 type Template struct {
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description"`
-	Type        string            `yaml:"type"`
-	Version     string            `yaml:"version"`
-	Config      map[string]string `yaml:"config"`
-	CreatedAt   time.Time         `yaml:"created_at"`
-	Tags        []string          `yaml:"tags,omitempty"`
+	SchemaVersion int               `json:"schema_version" yaml:"schema_version"`
+	Name          string            `json:"name" yaml:"name"`
+	Description   string            `json:"description" yaml:"description"`
+	Type          string            `json:"type" yaml:"type"`
+	Version       string            `json:"version" yaml:"version"`
+	Config        map[string]string `json:"config" yaml:"config"`
+	CreatedAt     time.Time         `json:"created_at" yaml:"created_at"`
+	Tags          []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 }
 
 type TemplateStore struct {
@@ -25,8 +28,7 @@ type TemplateStore struct {
 }
 
 func NewTemplateStore() *TemplateStore {
-	home, _ := os.UserHomeDir()
-	templatesDir := filepath.Join(home, ".spindb", "templates")
+	templatesDir := filepath.Join(utils.SpinDBHome(), "templates")
 	os.MkdirAll(templatesDir, 0755)
 
 	return &TemplateStore{
@@ -34,10 +36,28 @@ func NewTemplateStore() *TemplateStore {
 	}
 }
 
+// Save moves a template password into the secrets backend; the file only
+// keeps the reference under config.password_ref.
 func (ts *TemplateStore) Save(template *Template) error {
+	template.SchemaVersion = SchemaVersion
 	template.CreatedAt = time.Now()
 
-	data, err := yaml.Marshal(template)
+	stored := *template
+	stored.Config = make(map[string]string, len(template.Config))
+	for key, value := range template.Config {
+		stored.Config[key] = value
+	}
+
+	if password := stored.Config["password"]; password != "" {
+		ref := secrets.TemplateKey(template.Name)
+		if err := storeSecret(ref, password); err != nil {
+			return err
+		}
+		stored.Config["password_ref"] = ref
+		delete(stored.Config, "password")
+	}
+
+	data, err := yaml.Marshal(&stored)
 	if err != nil {
 		return fmt.Errorf("failed to marshal template: %w", err)
 	}
@@ -45,7 +65,13 @@ func (ts *TemplateStore) Save(template *Template) error {
 	filename := fmt.Sprintf("%s.yaml", template.Name)
 	filepath := filepath.Join(ts.templatesDir, filename)
 
-	return ioutil.WriteFile(filepath, data, 0644)
+	lock, err := ts.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return utils.WriteFileAtomic(filepath, data, 0600)
 }
 
 func (ts *TemplateStore) Load(name string) (*Template, error) {
@@ -62,6 +88,11 @@ func (ts *TemplateStore) Load(name string) (*Template, error) {
 		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
+	if ref := template.Config["password_ref"]; ref != "" {
+		template.Config["password"] = resolveSecret(ref)
+		delete(template.Config, "password_ref")
+	}
+
 	return &template, nil
 }
 
@@ -90,7 +121,22 @@ func (ts *TemplateStore) Delete(name string) error {
 	filename := fmt.Sprintf("%s.yaml", name)
 	filepath := filepath.Join(ts.templatesDir, filename)
 
-	return os.Remove(filepath)
+	lock, err := ts.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	if err := os.Remove(filepath); err != nil {
+		return err
+	}
+
+	deleteSecret(secrets.TemplateKey(name))
+	return nil
+}
+
+func (ts *TemplateStore) lock() (*utils.FileLock, error) {
+	return utils.LockFile(filepath.Join(ts.templatesDir, ".lock"))
 }
 
 func (ts *TemplateStore) Exists(name string) bool {
@@ -107,6 +153,11 @@ func (ts *TemplateStore) Export(name, exportPath string) error {
 		return err
 	}
 
+	// Exported templates are meant to be shared, so credentials stay behind.
+	delete(template.Config, "password")
+	delete(template.Config, "password_ref")
+
+	template.SchemaVersion = SchemaVersion
 	data, err := yaml.Marshal(template)
 	if err != nil {
 		return fmt.Errorf("failed to marshal template: %w", err)
@@ -121,6 +172,12 @@ func (ts *TemplateStore) Import(importPath string) error {
 		return fmt.Errorf("failed to read template file: %w", err)
 	}
 
+	if migrated, result, err := migrateDocument(data, templateMigrations, &MigrationContext{}); err != nil {
+		return fmt.Errorf("failed to upgrade template file: %w", err)
+	} else if result != nil {
+		data = migrated
+	}
+
 	var template Template
 	if err := yaml.Unmarshal(data, &template); err != nil {
 		return fmt.Errorf("failed to parse template file: %w", err)
@@ -137,9 +194,8 @@ func GetPredefinedTemplates() []*Template {
 			Type:        "postgres",
 			Version:     "15",
 			Config: map[string]string{
-				"user":     "dev_user",
-				"password": "dev_password",
-				"port":     "5432",
+				"user": "dev_user",
+				"port": "5432",
 			},
 			Tags: []string{"dev", "postgres"},
 		},
@@ -149,9 +205,8 @@ func GetPredefinedTemplates() []*Template {
 			Type:        "postgres",
 			Version:     "15",
 			Config: map[string]string{
-				"user":     "test_user",
-				"password": "test_password",
-				"port":     "5433",
+				"user": "test_user",
+				"port": "5433",
 			},
 			Tags: []string{"test", "postgres"},
 		},
@@ -161,9 +216,8 @@ func GetPredefinedTemplates() []*Template {
 			Type:        "mysql",
 			Version:     "8.0",
 			Config: map[string]string{
-				"user":     "dev_user",
-				"password": "dev_password",
-				"port":     "3306",
+				"user": "dev_user",
+				"port": "3306",
 			},
 			Tags: []string{"dev", "mysql"},
 		},
@@ -173,11 +227,32 @@ func GetPredefinedTemplates() []*Template {
 			Type:        "mysql",
 			Version:     "8.0",
 			Config: map[string]string{
-				"user":     "test_user",
-				"password": "test_password",
-				"port":     "3307",
+				"user": "test_user",
+				"port": "3307",
 			},
 			Tags: []string{"test", "mysql"},
 		},
+		{
+			Name:        "mariadb-dev",
+			Description: "MariaDB development environment",
+			Type:        "mariadb",
+			Version:     "11.4",
+			Config: map[string]string{
+				"user": "dev_user",
+				"port": "3306",
+			},
+			Tags: []string{"dev", "mariadb"},
+		},
+		{
+			Name:        "mongo-dev",
+			Description: "MongoDB development environment",
+			Type:        "mongo",
+			Version:     "7",
+			Config: map[string]string{
+				"user": "dev_user",
+				"port": "27017",
+			},
+			Tags: []string{"dev", "mongo"},
+		},
 	}
 }
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/utils"
+)
+
+type BranchOptions struct {
+	Port   int
+	Public bool
+}
+
+// Branch forks a Docker database by copying its data directory, reflinked
+// where the filesystem allows, into a new instance. The parent is stopped for
+// the copy so the branch starts from a consistent state.
+func (m *Manager) Branch(parentName, branchName string, opts *BranchOptions) error {
+	if opts == nil {
+		opts = &BranchOptions{}
+	}
+
+	if err := utils.ValidateDatabaseName(branchName); err != nil {
+		return err
+	}
+
+	parent, err := m.findDatabase(parentName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.findDatabase(branchName); err == nil {
+		return fmt.Errorf("database '%s' already exists", branchName)
+	}
+
+	engine, err := GetEngine(parent.Type)
+	if err != nil {
+		return err
+	}
+
+	if !engine.Containerized() {
+		return fmt.Errorf("branching is only supported for Docker databases, use 'spindb clone' for %s", engine.DisplayName())
+	}
+
+	image, err := m.copyDataDir(engine, parent, branchName)
+	if err != nil {
+		return err
+	}
+
+	err = m.Create(engine.Name(), &InstanceConfig{
+		Name:         branchName,
+		Database:     parent.DatabaseName(),
+		Parent:       parent.Name,
+		User:         parent.User,
+		Password:     parent.Password,
+		Port:         opts.Port,
+		Version:      parent.Version,
+		Public:       opts.Public,
+		Extensions:   parent.Extensions,
+		ExistingData: true,
+	})
+	if err != nil {
+		m.dockerService.RemoveDataDir(context.Background(), image, m.dataDir(parent.Type, branchName))
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	fmt.Printf("✅ Branch '%s' created from '%s'\n", branchName, parentName)
+	return nil
+}
+
+// copyDataDir copies the data directory of source to the one of newName. A
+// running source is stopped for the copy, so the copy starts from a
+// consistent state. It returns the image used for privileged file
+// operations, for removing the copy again.
+func (m *Manager) copyDataDir(engine Engine, source *config.DatabaseConfig, newName string) (string, error) {
+	if source.ContainerID == "" {
+		return "", fmt.Errorf("no container ID found for database '%s'", source.Name)
+	}
+
+	if m.dockerService == nil {
+		return "", fmt.Errorf("docker service not available")
+	}
+
+	targetDir := m.dataDir(source.Type, newName)
+	if hasData, err := utils.DirHasData(targetDir); err != nil || hasData {
+		return "", fmt.Errorf("data directory %s is not empty, remove it first", targetDir)
+	}
+
+	ctx := context.Background()
+
+	image := engine.Image(source.Version)
+	if container, err := m.dockerService.GetContainer(ctx, source.ContainerID); err == nil {
+		image = container.Config.Image
+	}
+
+	running, err := m.dockerService.IsContainerRunning(ctx, source.ContainerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check container status: %w", err)
+	}
+
+	if running {
+		fmt.Printf("Stopping database '%s'...\n", source.Name)
+		if err := m.dockerService.StopContainer(ctx, source.ContainerID); err != nil {
+			return "", fmt.Errorf("failed to stop container: %w", err)
+		}
+	}
+
+	fmt.Printf("Copying data directory of '%s'...\n", source.Name)
+	copyErr := m.dockerService.CopyDataDir(ctx, image, m.dataDir(source.Type, source.Name), targetDir)
+
+	if running {
+		fmt.Printf("Starting database '%s'...\n", source.Name)
+		if err := m.dockerService.StartContainer(ctx, source.ContainerID); err != nil {
+			fmt.Printf("⚠️  Failed to restart '%s': %v\n", source.Name, err)
+		} else if err := WaitForEngine(engine, source, 60*time.Second); err != nil {
+			fmt.Printf("⚠️  '%s' is not ready yet: %v\n", source.Name, err)
+		}
+	}
+
+	if copyErr != nil {
+		m.dockerService.RemoveDataDir(ctx, image, targetDir)
+		return "", fmt.Errorf("failed to copy data directory: %w", copyErr)
+	}
+
+	return image, nil
+}
+
+// ListBranches returns the database followed by every branch descending from
+// it, depth first and oldest first, so each branch comes after its parent.
+func (m *Manager) ListBranches(name string) ([]DatabaseInfo, error) {
+	root, err := m.findDatabase(name)
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := m.store.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load databases: %w", err)
+	}
+
+	children := make(map[string][]config.DatabaseConfig)
+	for _, db := range databases {
+		if db.Parent != "" {
+			children[db.Parent] = append(children[db.Parent], db)
+		}
+	}
+
+	infos := []DatabaseInfo{m.newDatabaseInfo(root, false)}
+	return m.appendBranches(infos, children, root.Name), nil
+}
+
+func (m *Manager) appendBranches(infos []DatabaseInfo, children map[string][]config.DatabaseConfig, parent string) []DatabaseInfo {
+	branches := children[parent]
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Created.Before(branches[j].Created)
+	})
+
+	for i := range branches {
+		infos = append(infos, m.newDatabaseInfo(&branches[i], false))
+		infos = m.appendBranches(infos, children, branches[i].Name)
+	}
+
+	return infos
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/awade12/spindb/internal/config"
+)
+
+type ClickHouseConfig = InstanceConfig
+
+type clickhouseEngine struct{}
+
+func init() {
+	RegisterEngine(&clickhouseEngine{})
+}
+
+func (e *clickhouseEngine) Name() string        { return "clickhouse" }
+func (e *clickhouseEngine) DisplayName() string { return "ClickHouse" }
+func (e *clickhouseEngine) Containerized() bool { return true }
+
+func (e *clickhouseEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.ClickHouse.Version,
+		Port:    cfg.Default.ClickHouse.Port,
+		User:    cfg.Default.ClickHouse.User,
+	}
+}
+
+func (e *clickhouseEngine) NamedPorts(cfg *config.Config) []NamedPort {
+	return []NamedPort{
+		{Name: "http", ContainerPort: "8123", DefaultPort: cfg.Default.ClickHouse.Port},
+		{Name: "native", ContainerPort: "9000", DefaultPort: cfg.Default.ClickHouse.NativePort},
+	}
+}
+
+func (e *clickhouseEngine) Image(version string) string {
+	return fmt.Sprintf("clickhouse/clickhouse-server:%s", version)
+}
+
+func (e *clickhouseEngine) ContainerPort() string { return "8123" }
+func (e *clickhouseEngine) DataMountPath() string { return "/var/lib/clickhouse" }
+
+func (e *clickhouseEngine) Env(cfg *InstanceConfig) []string {
+	return []string{
+		fmt.Sprintf("CLICKHOUSE_DB=%s", cfg.DatabaseName()),
+		fmt.Sprintf("CLICKHOUSE_USER=%s", cfg.User),
+		fmt.Sprintf("CLICKHOUSE_PASSWORD=%s", cfg.Password),
+		"CLICKHOUSE_DEFAULT_ACCESS_MANAGEMENT=1",
+	}
+}
+
+func (e *clickhouseEngine) DSN(db *config.DatabaseConfig) string {
+	return e.URL("localhost", db)
+}
+
+func (e *clickhouseEngine) URL(host string, db *config.DatabaseConfig) string {
+	u := &url.URL{
+		Scheme: "clickhouse",
+		User:   url.UserPassword(db.User, db.Password),
+		Host:   net.JoinHostPort(host, strconv.Itoa(e.nativePort(db))),
+		Path:   "/" + db.DatabaseName(),
+	}
+	return u.String()
+}
+
+func (e *clickhouseEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return []EnvVar{
+		{"CLICKHOUSE_HOST", host},
+		{"CLICKHOUSE_PORT", strconv.Itoa(e.nativePort(db))},
+		{"CLICKHOUSE_HTTP_PORT", strconv.Itoa(db.Port)},
+		{"CLICKHOUSE_USER", db.User},
+		{"CLICKHOUSE_PASSWORD", db.Password},
+		{"CLICKHOUSE_DB", db.DatabaseName()},
+	}
+}
+
+func (e *clickhouseEngine) nativePort(db *config.DatabaseConfig) int {
+	if port, ok := db.Ports["native"]; ok {
+		return port
+	}
+	return db.Port
+}
+
+func (e *clickhouseEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "docker",
+		Args: []string{
+			"exec", "-it", shortContainerID(db.ContainerID),
+			"clickhouse-client",
+			"--user", db.User,
+			"--password", db.Password,
+			"--database", db.DatabaseName(),
+		},
+		InstallHints: []string{
+			"clickhouse-client runs inside the container, so only the Docker CLI is needed",
+			"See: https://docs.docker.com/get-docker/",
+		},
+	}
+}
+
+func (e *clickhouseEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestClickHouse("localhost", db.Port, db.User, db.Password)
+}
+
+func (e *clickhouseEngine) BackupExtension() string { return ".native" }
+
+func (e *clickhouseEngine) dumpless() {}
+
+func (e *clickhouseEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"clickhouse-client",
+		"--user", db.User,
+		"--password", db.Password,
+		"--database", db.DatabaseName(),
+		"--multiquery",
+	}
+
+	return runRestore(db, cmd, nil, r)
+}
+
+func (e *clickhouseEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	return fmt.Errorf("backups are not supported for ClickHouse")
+}
+
+func (e *clickhouseEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	return fmt.Errorf("restores are not supported for ClickHouse")
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/utils"
+)
+
+type CloneOptions struct {
+	Port   int
+	Public bool
+}
+
+func (m *Manager) Clone(sourceName, newName string, opts *CloneOptions) error {
+	if opts == nil {
+		opts = &CloneOptions{}
+	}
+
+	source, err := m.findDatabase(sourceName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.findDatabase(newName); err == nil {
+		return fmt.Errorf("database '%s' already exists", newName)
+	}
+
+	engine, err := GetEngine(source.Type)
+	if err != nil {
+		return err
+	}
+
+	if engine.Containerized() {
+		if err := utils.ValidateDatabaseName(newName); err != nil {
+			return err
+		}
+	}
+
+	if !SupportsDumps(engine) {
+		return m.cloneDataDir(engine, source, newName, opts)
+	}
+
+	cfg := &InstanceConfig{
+		Name:       newName,
+		User:       source.User,
+		Password:   source.Password,
+		Port:       opts.Port,
+		Version:    source.Version,
+		Public:     opts.Public,
+		Extensions: source.Extensions,
+	}
+	if !engine.Containerized() {
+		cfg.FilePath = filepath.Join(filepath.Dir(source.FilePath), newName)
+		newName = filepath.Base(cfg.FilePath)
+	}
+
+	fmt.Printf("Provisioning %s instance '%s' from '%s'...\n", engine.DisplayName(), newName, sourceName)
+	if err := m.Create(engine.Name(), cfg); err != nil {
+		return fmt.Errorf("failed to create clone: %w", err)
+	}
+
+	target, err := m.findDatabase(newName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copying data from '%s' to '%s'...\n", sourceName, newName)
+	if err := copyDatabase(engine, source, target); err != nil {
+		fmt.Printf("Removing incomplete clone '%s'...\n", newName)
+		if removeErr := m.Remove(newName, true); removeErr != nil {
+			fmt.Printf("⚠️  Failed to remove '%s': %v\n", newName, removeErr)
+		}
+		return fmt.Errorf("failed to copy data: %w", err)
+	}
+
+	fmt.Printf("✅ Database '%s' cloned to '%s'\n", sourceName, newName)
+	return nil
+}
+
+// cloneDataDir clones engines that cannot stream a dump by copying the data
+// directory while the source is stopped, like a branch without the parent
+// link.
+func (m *Manager) cloneDataDir(engine Engine, source *config.DatabaseConfig, newName string, opts *CloneOptions) error {
+	fmt.Printf("%s cannot stream a dump, copying the data directory of '%s' instead\n", engine.DisplayName(), source.Name)
+	image, err := m.copyDataDir(engine, source, newName)
+	if err != nil {
+		return err
+	}
+
+	err = m.Create(engine.Name(), &InstanceConfig{
+		Name:         newName,
+		Database:     source.DatabaseName(),
+		User:         source.User,
+		Password:     source.Password,
+		Port:         opts.Port,
+		Version:      source.Version,
+		Public:       opts.Public,
+		Extensions:   source.Extensions,
+		ExistingData: true,
+	})
+	if err != nil {
+		m.dockerService.RemoveDataDir(context.Background(), image, m.dataDir(source.Type, newName))
+		return fmt.Errorf("failed to create clone: %w", err)
+	}
+
+	fmt.Printf("✅ Database '%s' cloned to '%s'\n", source.Name, newName)
+	return nil
+}
+
+func copyDatabase(engine Engine, source, target *config.DatabaseConfig) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError(engine.Backup(source, nil, pw))
+	}()
+
+	err := engine.Restore(target, pr)
+	pr.CloseWithError(err)
+	return err
+}
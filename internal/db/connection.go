@@ -1,13 +1,25 @@
 package db
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
+	_ "github.com/microsoft/go-mssqldb"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 	_ "modernc.org/sqlite"
 )
 
@@ -25,15 +37,162 @@ func (ct *ConnectionTester) TestPostgres(host string, port int, user, password,
 }
 
 func (ct *ConnectionTester) TestMySQL(host string, port int, user, password, dbname string) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", user, password, host, port, dbname)
+	return ct.testConnection("mysql", MySQLDSN(host, port, user, password, dbname))
+}
+
+func (ct *ConnectionTester) TestMariaDB(host string, port int, user, password, dbname string) error {
+	return ct.TestMySQL(host, port, user, password, dbname)
+}
+
+func MySQLDSN(host string, port int, user, password, dbname string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", user, password, host, port, dbname)
+}
 
-	return ct.testConnection("mysql", dsn)
+func (ct *ConnectionTester) TestMSSQL(host string, port int, user, password, dbname string) error {
+	return ct.testConnection("sqlserver", MSSQLDSN(host, port, user, password, dbname))
+}
+
+func MSSQLDSN(host string, port int, user, password, dbname string) string {
+	query := url.Values{}
+	query.Set("database", dbname)
+	query.Set("encrypt", "disable")
+
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(user, password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+		RawQuery: query.Encode(),
+	}
+	return u.String()
 }
 
 func (ct *ConnectionTester) TestSQLite(filePath string) error {
 	return ct.testConnection("sqlite", filePath)
 }
 
+func (ct *ConnectionTester) TestRedis(host string, port int, password string) error {
+	reply, err := ct.RedisCommand(host, port, password, "PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected PING reply: %s", reply)
+	}
+
+	return nil
+}
+
+func (ct *ConnectionTester) TestClickHouse(host string, port int, user, password string) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	baseURL := fmt.Sprintf("http://%s/", net.JoinHostPort(host, strconv.Itoa(port)))
+
+	resp, err := client.Get(baseURL + "ping")
+	if err != nil {
+		return fmt.Errorf("failed to open connection: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ping returned status %s", resp.Status)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, baseURL+"?query="+url.QueryEscape("SELECT 1"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-ClickHouse-User", user)
+	req.Header.Set("X-ClickHouse-Key", password)
+
+	resp, err = client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query database: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("query failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+func (ct *ConnectionTester) TestMongo(uri string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetServerSelectionTimeout(5 * time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return nil
+}
+
+func (ct *ConnectionTester) RunMongoCommand(uri, database string, command any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetServerSelectionTimeout(5 * time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	return client.Database(database).RunCommand(ctx, command).Err()
+}
+
+func (ct *ConnectionTester) RedisCommand(host string, port int, password string, args ...string) (string, error) {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), 10*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	reader := bufio.NewReader(conn)
+
+	if password != "" {
+		if _, err := redisCommand(conn, reader, "AUTH", password); err != nil {
+			return "", fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	reply, err := redisCommand(conn, reader, args...)
+	if err != nil {
+		return "", fmt.Errorf("%s failed: %w", args[0], err)
+	}
+
+	return reply, nil
+}
+
+func redisCommand(conn net.Conn, reader *bufio.Reader, args ...string) (string, error) {
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := conn.Write([]byte(cmd.String())); err != nil {
+		return "", err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+
+	if strings.HasPrefix(line, "-") {
+		return "", fmt.Errorf("%s", strings.TrimPrefix(line, "-"))
+	}
+
+	return strings.TrimPrefix(line, "+"), nil
+}
+
 func (ct *ConnectionTester) testConnection(driver, dsn string) error {
 	db, err := sql.Open(driver, dsn)
 	if err != nil {
@@ -55,6 +214,141 @@ func (ct *ConnectionTester) testConnection(driver, dsn string) error {
 	return nil
 }
 
+func (ct *ConnectionTester) Exec(driver, dsn, query string, args ...any) error {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	if _, err := db.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TableCounts runs listQuery, which returns one table name per row, and
+// counts the rows of each table. quote makes a name safe to use in a query.
+func (ct *ConnectionTester) TableCounts(driver, dsn, listQuery string, quote func(string) string) (map[string]int64, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	counts := make(map[string]int64)
+	for _, table := range tables {
+		var count int64
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quote(table)).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count rows in %s: %w", table, err)
+		}
+		counts[table] = count
+	}
+
+	return counts, nil
+}
+
+// QueryTruthy reports whether query returns a first value that is neither
+// NULL, false, zero nor empty.
+func (ct *ConnectionTester) QueryTruthy(driver, dsn, query string) (bool, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return false, fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return false, err
+	}
+
+	values := make([]any, len(columns))
+	for i := range values {
+		values[i] = new(sql.RawBytes)
+	}
+	if err := rows.Scan(values...); err != nil {
+		return false, err
+	}
+
+	value := values[0].(*sql.RawBytes)
+	if *value == nil {
+		return false, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(*value))) {
+	case "", "0", "f", "false":
+		return false, nil
+	}
+	return true, nil
+}
+
+func (ct *ConnectionTester) MongoCollectionCounts(uri, database string) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetServerSelectionTimeout(5 * time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection: %w", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	db := client.Database(database)
+	names, err := db.ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	counts := make(map[string]int64)
+	for _, name := range names {
+		count, err := db.Collection(name).CountDocuments(ctx, bson.D{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count documents in %s: %w", name, err)
+		}
+		counts[name] = count
+	}
+
+	return counts, nil
+}
+
 func (ct *ConnectionTester) WaitForDatabase(driver, dsn string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awade12/spindb/internal/utils"
+)
+
+// RotateCredentials changes the password of a running instance and records
+// it in the registry and secrets store. An empty password generates one.
+func (m *Manager) RotateCredentials(name, password string) error {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return err
+	}
+
+	engine, err := GetEngine(target.Type)
+	if err != nil {
+		return err
+	}
+
+	rotator, ok := engine.(CredentialRotator)
+	if !ok {
+		return fmt.Errorf("credential rotation is not supported for %s", engine.DisplayName())
+	}
+
+	if m.dockerService == nil {
+		return fmt.Errorf("docker service not available")
+	}
+
+	running, err := m.dockerService.IsContainerRunning(context.Background(), target.ContainerID)
+	if err != nil {
+		return fmt.Errorf("failed to check container status: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("database '%s' is not running, start it with 'spindb start %s'", name, name)
+	}
+
+	if password == "" {
+		password, err = utils.GeneratePassword()
+		if err != nil {
+			return err
+		}
+	}
+
+	if validator, ok := engine.(ValidatingEngine); ok {
+		if err := validator.Validate(&InstanceConfig{User: target.User, Password: password}); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Rotating password for %s user '%s' on '%s'...\n", engine.DisplayName(), target.User, name)
+	if err := rotator.RotatePassword(target, password); err != nil {
+		return fmt.Errorf("failed to rotate password: %w", err)
+	}
+
+	target.Password = password
+	if err := m.store.Save(target); err != nil {
+		// The server already uses the new password, so it must not be lost.
+		return fmt.Errorf("password was changed to %q but could not be saved: %w", password, err)
+	}
+
+	if err := engine.Ping(target); err != nil {
+		return fmt.Errorf("password was rotated but the new credentials failed to connect: %w", err)
+	}
+
+	fmt.Printf("✅ Credentials for '%s' rotated successfully!\n", name)
+	fmt.Printf("   URL: run 'spindb url %s' to view it with the new password\n", name)
+	return nil
+}
@@ -0,0 +1,291 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/docker"
+)
+
+// Engine describes everything SpinDB needs to know to provision, connect to,
+// probe and back up one kind of database. Engines register themselves with
+// RegisterEngine and are looked up by the Type stored in DatabaseConfig.
+type Engine interface {
+	Name() string
+	DisplayName() string
+	Containerized() bool
+	Defaults(cfg *config.Config) EngineDefaults
+
+	Image(version string) string
+	ContainerPort() string
+	DataMountPath() string
+	Env(cfg *InstanceConfig) []string
+
+	DSN(db *config.DatabaseConfig) string
+	URL(host string, db *config.DatabaseConfig) string
+	Client(host string, db *config.DatabaseConfig) *Client
+	Ping(db *config.DatabaseConfig) error
+
+	BackupExtension() string
+	Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error
+	Restore(db *config.DatabaseConfig, r io.Reader) error
+}
+
+// CommandEngine is implemented by engines whose container needs arguments
+// beyond the image's default command.
+type CommandEngine interface {
+	Command(cfg *InstanceConfig) []string
+}
+
+// HealthcheckEngine is implemented by engines that ship a Docker
+// healthcheck for their container.
+type HealthcheckEngine interface {
+	Healthcheck() []string
+}
+
+// ContainerCustomizer is implemented by engines whose container needs
+// changes the other hooks do not cover, such as running as another user.
+// It runs last, so it sees the command and healthcheck already set.
+type ContainerCustomizer interface {
+	CustomizeContainer(container *docker.ContainerConfig, cfg *InstanceConfig)
+}
+
+// MultiPortEngine is implemented by engines that publish more than one
+// container port. The entry matching ContainerPort is the primary port.
+type MultiPortEngine interface {
+	NamedPorts(cfg *config.Config) []NamedPort
+}
+
+type NamedPort struct {
+	Name          string
+	ContainerPort string
+	DefaultPort   int
+}
+
+// ExtensionEngine is implemented by engines that can enable optional
+// extensions on a new instance, listed by name.
+type ExtensionEngine interface {
+	Extensions() []string
+}
+
+// DataOwnerEngine is implemented by engines whose container runs as a fixed
+// non-root user that cannot take over the data directory itself. It returns
+// the uid:gid to chown the directory to, or "" when nothing is needed.
+type DataOwnerEngine interface {
+	DataDirOwner(cfg *InstanceConfig) string
+}
+
+// dumplessEngine is implemented by engines whose Backup and Restore always
+// fail, so callers can choose another route before provisioning anything.
+type dumplessEngine interface {
+	dumpless()
+}
+
+// SupportsDumps reports whether Backup and Restore work for engine.
+func SupportsDumps(engine Engine) bool {
+	_, dumpless := engine.(dumplessEngine)
+	return !dumpless
+}
+
+// ValidatingEngine is implemented by engines that need to reject an
+// instance configuration before any container is created.
+type ValidatingEngine interface {
+	Validate(cfg *InstanceConfig) error
+}
+
+// InitializingEngine is implemented by engines that need extra setup once the
+// server answers, such as creating the database the image does not create.
+type InitializingEngine interface {
+	Initialize(db *config.DatabaseConfig) error
+}
+
+// CredentialRotator is implemented by engines that can change the password
+// of the instance user while the server is running. db still holds the
+// current password.
+type CredentialRotator interface {
+	RotatePassword(db *config.DatabaseConfig, password string) error
+}
+
+// EnvVarEngine is implemented by engines whose clients read conventional
+// environment variables, such as PGHOST for PostgreSQL.
+type EnvVarEngine interface {
+	EnvVars(host string, db *config.DatabaseConfig) []EnvVar
+}
+
+// ScriptEngine is implemented by engines that can run a file of statements
+// or commands with their own client, such as psql -f for PostgreSQL. It is
+// what manifest init scripts go through; Restore only takes dumps.
+type ScriptEngine interface {
+	RunScript(db *config.DatabaseConfig, r io.Reader) error
+}
+
+// StrictRestoringEngine is implemented by engines whose Restore carries on
+// past failing statements. RestoreStrict stops at the first error and
+// applies nothing, so a partial restore cannot pass for a good one.
+type StrictRestoringEngine interface {
+	RestoreStrict(db *config.DatabaseConfig, r io.Reader) error
+}
+
+// VerifyingEngine is implemented by engines that can summarise a restored
+// database, so backup verification can show what a backup contains.
+type VerifyingEngine interface {
+	TableCounts(db *config.DatabaseConfig) (map[string]int64, error)
+}
+
+// AssertingEngine is implemented by engines that can check a user-supplied
+// query against a restored database. An assertion passes when the query's
+// first column of its first row is neither NULL, false, zero nor empty.
+type AssertingEngine interface {
+	Assert(db *config.DatabaseConfig, query string) (bool, error)
+}
+
+type EnvVar struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+type EngineDefaults struct {
+	Version string
+	Port    int
+	User    string
+}
+
+type InstanceConfig struct {
+	Name       string
+	Database   string
+	Parent     string
+	User       string
+	Password   string
+	Port       int
+	Version    string
+	Public     bool
+	FilePath   string
+	Extensions []string
+	// ExistingData is set by callers that filled the data directory
+	// themselves, such as branch, so Create takes it over as it is.
+	ExistingData bool
+}
+
+func (c *InstanceConfig) DatabaseName() string {
+	if c.Database != "" {
+		return c.Database
+	}
+	return c.Name
+}
+
+type DumpOptions struct {
+	SchemaOnly bool
+	DataOnly   bool
+}
+
+type Client struct {
+	Command           string
+	Args              []string
+	Env               []string
+	InstallHints      []string
+	DockerAlternative string
+}
+
+func (c *Client) String() string {
+	return strings.Join(append([]string{c.Command}, c.Args...), " ")
+}
+
+var (
+	enginesMu sync.RWMutex
+	engines   = map[string]Engine{}
+)
+
+func RegisterEngine(engine Engine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+
+	name := engine.Name()
+	if _, exists := engines[name]; exists {
+		panic(fmt.Sprintf("db: engine %q registered twice", name))
+	}
+	engines[name] = engine
+}
+
+func GetEngine(name string) (Engine, error) {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	engine, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported database type: %s", name)
+	}
+	return engine, nil
+}
+
+func Engines() []Engine {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+
+	var list []Engine
+	for _, engine := range engines {
+		list = append(list, engine)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+	return list
+}
+
+func EngineNames() []string {
+	var names []string
+	for _, engine := range Engines() {
+		names = append(names, engine.Name())
+	}
+	return names
+}
+
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// runDump runs one of the engine's own dump tools inside the database
+// container and streams its output to w, so backups never depend on client
+// tools installed on the host or on their version matching the server.
+func runDump(db *config.DatabaseConfig, cmd, env []string, w io.Writer) error {
+	return execInContainer(db, cmd, env, nil, w)
+}
+
+func runRestore(db *config.DatabaseConfig, cmd, env []string, r io.Reader) error {
+	return execInContainer(db, cmd, env, r, nil)
+}
+
+func execInContainer(db *config.DatabaseConfig, cmd, env []string, stdin io.Reader, stdout io.Writer) error {
+	if db.ContainerID == "" {
+		return fmt.Errorf("database '%s' has no container", db.Name)
+	}
+
+	dockerSvc, err := docker.NewService()
+	if err != nil {
+		return err
+	}
+	defer dockerSvc.Close()
+
+	return dockerSvc.Exec(context.Background(), db.ContainerID, cmd, env, stdin, stdout)
+}
+
+func WaitForEngine(engine Engine, db *config.DatabaseConfig, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		if err := engine.Ping(db); err == nil {
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	return fmt.Errorf("database did not become available within %v", timeout)
+}
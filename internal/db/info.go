@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+)
+
+const (
+	StatusRunning           = "running"
+	StatusStopped           = "stopped"
+	StatusAvailable         = "available"
+	StatusMissing           = "missing"
+	StatusUnknown           = "unknown"
+	StatusDockerUnavailable = "docker-unavailable"
+	StatusError             = "error"
+)
+
+// DatabaseInfo is the read-only view of a managed database returned to the
+// cmd layer for rendering. Password is only set when credentials were
+// explicitly requested.
+type DatabaseInfo struct {
+	Name          string         `json:"name" yaml:"name"`
+	Type          string         `json:"type" yaml:"type"`
+	Version       string         `json:"version,omitempty" yaml:"version,omitempty"`
+	Status        string         `json:"status" yaml:"status"`
+	Containerized bool           `json:"containerized" yaml:"containerized"`
+	Port          int            `json:"port,omitempty" yaml:"port,omitempty"`
+	Ports         map[string]int `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Public        bool           `json:"public" yaml:"public"`
+	Database      string         `json:"database,omitempty" yaml:"database,omitempty"`
+	Parent        string         `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Extensions    []string       `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	FilePath      string         `json:"file_path,omitempty" yaml:"file_path,omitempty"`
+	User          string         `json:"user,omitempty" yaml:"user,omitempty"`
+	Password      string         `json:"password,omitempty" yaml:"password,omitempty"`
+	PasswordRef   string         `json:"password_ref,omitempty" yaml:"password_ref,omitempty"`
+	ContainerID   string         `json:"container_id,omitempty" yaml:"container_id,omitempty"`
+	Created       time.Time      `json:"created" yaml:"created"`
+	LastUsed      *time.Time     `json:"last_used,omitempty" yaml:"last_used,omitempty"`
+}
+
+// StatusLabel is the decorated form of a status used by the table output.
+func StatusLabel(status string) string {
+	switch status {
+	case StatusRunning:
+		return "✅ Running"
+	case StatusStopped:
+		return "⏸️ Stopped"
+	case StatusAvailable:
+		return "✅ Available"
+	case StatusMissing:
+		return "❌ File not found"
+	case StatusDockerUnavailable:
+		return "❓ Docker unavailable"
+	case StatusError:
+		return "❌ Error checking status"
+	default:
+		return "❓ Unknown"
+	}
+}
+
+func (m *Manager) ListDatabases(dbType string, showCredentials bool) ([]DatabaseInfo, error) {
+	databases, err := m.store.List(dbType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load databases: %w", err)
+	}
+
+	infos := []DatabaseInfo{}
+	for i := range databases {
+		infos = append(infos, m.newDatabaseInfo(&databases[i], showCredentials))
+	}
+
+	return infos, nil
+}
+
+func (m *Manager) Info(name string, showCredentials bool) (*DatabaseInfo, error) {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := m.newDatabaseInfo(target, showCredentials)
+	return &info, nil
+}
+
+func (m *Manager) newDatabaseInfo(db *config.DatabaseConfig, showCredentials bool) DatabaseInfo {
+	info := DatabaseInfo{
+		Name:          db.Name,
+		Type:          db.Type,
+		Version:       db.Version,
+		Status:        m.status(db),
+		Containerized: m.isContainerized(db.Type),
+		Port:          db.Port,
+		Ports:         db.Ports,
+		Public:        db.Public,
+		Parent:        db.Parent,
+		Extensions:    db.Extensions,
+		FilePath:      db.FilePath,
+		User:          db.User,
+		PasswordRef:   db.PasswordRef,
+		ContainerID:   db.ContainerID,
+		Created:       db.Created,
+	}
+
+	if info.Containerized {
+		info.Database = db.DatabaseName()
+	}
+
+	if showCredentials {
+		info.Password = db.Password
+	}
+
+	if !db.LastUsed.IsZero() {
+		lastUsed := db.LastUsed
+		info.LastUsed = &lastUsed
+	}
+
+	return info
+}
+
+func (m *Manager) status(db *config.DatabaseConfig) string {
+	if !m.isContainerized(db.Type) {
+		if _, err := os.Stat(db.FilePath); err != nil {
+			return StatusMissing
+		}
+		return StatusAvailable
+	}
+
+	if db.ContainerID == "" {
+		return StatusUnknown
+	}
+
+	if m.dockerService == nil {
+		return StatusDockerUnavailable
+	}
+
+	running, err := m.dockerService.IsContainerRunning(context.Background(), db.ContainerID)
+	if err != nil {
+		return StatusError
+	}
+
+	if running {
+		return StatusRunning
+	}
+	return StatusStopped
+}
@@ -6,25 +6,41 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/awade12/spindb/internal/config"
 	"github.com/awade12/spindb/internal/docker"
+	"github.com/awade12/spindb/internal/utils"
 )
 
 type DatabaseManager interface {
 	CreatePostgres(cfg *PostgresConfig) error
 	CreateMySQL(cfg *MySQLConfig) error
+	CreateMariaDB(cfg *MariaDBConfig) error
+	CreateClickHouse(cfg *ClickHouseConfig) error
+	CreateMSSQL(cfg *MSSQLConfig) error
+	CreateMongo(cfg *MongoConfig) error
+	CreateRedis(cfg *RedisConfig) error
 	CreateSQLite(cfg *SQLiteConfig) error
-	ListDatabases(dbType string) error
+	Create(engineName string, cfg *InstanceConfig) error
+	ListDatabases(dbType string, showCredentials bool) ([]DatabaseInfo, error)
 	Connect(name string, testOnly bool) error
-	ShowInfo(name string, showCredentials bool) error
+	Info(name string, showCredentials bool) (*DatabaseInfo, error)
 	Delete(name, file string, force bool) error
+	Remove(name string, removeData bool) error
 	Start(name string) error
 	Stop(name string) error
 	Restart(name string) error
+	Clone(sourceName, newName string, opts *CloneOptions) error
+	Branch(parentName, branchName string, opts *BranchOptions) error
+	ListBranches(name string) ([]DatabaseInfo, error)
+	RotateCredentials(name, password string) error
+	URL(name, host string) (string, error)
+	EnvVars(name, host, prefix string) ([]EnvVar, error)
 }
 
 type Manager struct {
@@ -49,107 +65,99 @@ func NewManager() *Manager {
 }
 
 func (m *Manager) CreatePostgres(cfg *PostgresConfig) error {
-	if m.dockerService == nil {
-		return fmt.Errorf("docker service not available")
-	}
-
-	if err := m.dockerService.IsDockerRunning(); err != nil {
-		return fmt.Errorf("docker is not running: %w", err)
-	}
+	return m.Create("postgres", cfg)
+}
 
-	port := cfg.Port
-	if port == 0 {
-		port = m.config.Default.Postgres.Port
-	}
+func (m *Manager) CreateMySQL(cfg *MySQLConfig) error {
+	return m.Create("mysql", cfg)
+}
 
-	availablePort, err := m.dockerService.FindAvailablePort(port)
-	if err != nil {
-		return fmt.Errorf("failed to find available port: %w", err)
-	}
+func (m *Manager) CreateMariaDB(cfg *MariaDBConfig) error {
+	return m.Create("mariadb", cfg)
+}
 
-	containerName := fmt.Sprintf("spindb-postgres-%s", cfg.Name)
-	image := fmt.Sprintf("postgres:%s", cfg.Version)
+func (m *Manager) CreateClickHouse(cfg *ClickHouseConfig) error {
+	return m.Create("clickhouse", cfg)
+}
 
-	ctx := context.Background()
+func (m *Manager) CreateMSSQL(cfg *MSSQLConfig) error {
+	return m.Create("mssql", cfg)
+}
 
-	fmt.Printf("Pulling PostgreSQL image %s...\n", image)
-	if err := m.dockerService.PullImage(ctx, image); err != nil {
-		return fmt.Errorf("failed to pull image: %w", err)
-	}
+func (m *Manager) CreateMongo(cfg *MongoConfig) error {
+	return m.Create("mongo", cfg)
+}
 
-	dataDir := filepath.Join(m.config.Storage.DataDir, "postgres", cfg.Name)
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return fmt.Errorf("failed to create data directory: %w", err)
-	}
+func (m *Manager) CreateRedis(cfg *RedisConfig) error {
+	return m.Create("redis", cfg)
+}
 
-	containerConfig := &docker.ContainerConfig{
-		Name:  containerName,
-		Image: image,
-		Env: []string{
-			fmt.Sprintf("POSTGRES_DB=%s", cfg.Name),
-			fmt.Sprintf("POSTGRES_USER=%s", cfg.User),
-			fmt.Sprintf("POSTGRES_PASSWORD=%s", cfg.Password),
-		},
-		Ports: map[string]string{
-			"5432": strconv.Itoa(availablePort),
-		},
-		Volumes: []string{
-			docker.CreateVolumeMount(dataDir, "/var/lib/postgresql/data"),
-		},
-		Public: cfg.Public,
-	}
+func (m *Manager) CreateSQLite(cfg *SQLiteConfig) error {
+	return m.Create("sqlite", &InstanceConfig{FilePath: cfg.FilePath})
+}
 
-	fmt.Printf("Creating PostgreSQL container %s...\n", containerName)
-	containerID, err := m.dockerService.CreateContainer(ctx, containerConfig)
+func (m *Manager) Create(engineName string, cfg *InstanceConfig) error {
+	engine, err := GetEngine(engineName)
 	if err != nil {
-		return fmt.Errorf("failed to create container: %w", err)
+		return err
 	}
 
-	fmt.Printf("Starting PostgreSQL container...\n")
-	if err := m.dockerService.StartContainer(ctx, containerID); err != nil {
-		m.dockerService.RemoveContainer(ctx, containerID, true)
-		return fmt.Errorf("failed to start container: %w", err)
+	if !engine.Containerized() {
+		return m.createFileDatabase(engine, cfg)
 	}
 
-	fmt.Printf("Waiting for PostgreSQL to be ready...\n")
-	dsn := fmt.Sprintf("host=localhost port=%d user=%s password=%s dbname=%s sslmode=disable",
-		availablePort, cfg.User, cfg.Password, cfg.Name)
-	if err := m.connTester.WaitForDatabase("postgres", dsn, 60*time.Second); err != nil {
-		return fmt.Errorf("PostgreSQL failed to start: %w", err)
+	return m.createContainerDatabase(engine, cfg)
+}
+
+func (m *Manager) createContainerDatabase(engine Engine, cfg *InstanceConfig) error {
+	defaults := engine.Defaults(m.config)
+	if cfg.Version == "" {
+		cfg.Version = defaults.Version
+	}
+	if cfg.User == "" {
+		cfg.User = defaults.User
 	}
 
-	dbConfig := &config.DatabaseConfig{
-		Name:        cfg.Name,
-		Type:        "postgres",
-		Version:     cfg.Version,
-		Port:        availablePort,
-		User:        cfg.User,
-		Password:    cfg.Password,
-		Public:      cfg.Public,
-		ContainerID: containerID,
-		Created:     time.Now(),
+	// delete keeps the data directory, and the server only applies the
+	// password when it initialises an empty one.
+	dataDir := m.dataDir(engine.Name(), cfg.Name)
+	hasData, err := utils.DirHasData(dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to check data directory: %w", err)
+	}
+	if hasData && !cfg.ExistingData {
+		if cfg.Password == "" {
+			kept, ok := config.KeptPassword(engine.Name(), cfg.Name)
+			if !ok {
+				return fmt.Errorf("data directory %s already holds data from an earlier '%s' instance; pass --password with the password it was created with, or remove the directory", dataDir, cfg.Name)
+			}
+			cfg.Password = kept
+			fmt.Printf("Reusing existing data directory %s with its stored password\n", dataDir)
+		} else {
+			fmt.Printf("⚠️  Reusing existing data directory %s; the password must match the one it was created with\n", dataDir)
+		}
 	}
 
-	if err := m.store.Save(dbConfig); err != nil {
-		return fmt.Errorf("failed to save database config: %w", err)
+	generatedPassword := cfg.Password == ""
+	if generatedPassword {
+		password, err := utils.GeneratePassword()
+		if err != nil {
+			return err
+		}
+		cfg.Password = password
 	}
 
-	fmt.Printf("✅ PostgreSQL database '%s' created successfully!\n", cfg.Name)
-	fmt.Printf("   Container ID: %s\n", containerID[:12])
-	fmt.Printf("   Port: %d\n", availablePort)
-	host := "localhost"
-	if cfg.Public {
-		host = "<your-server-ip>"
-		fmt.Printf("   Public: Yes (accessible externally)\n")
-	} else {
-		fmt.Printf("   Public: No (localhost only)\n")
+	cfg.Extensions = uniqueExtensions(cfg.Extensions)
+	if err := validateExtensions(engine, cfg.Extensions); err != nil {
+		return err
 	}
-	fmt.Printf("   Connection: psql -h %s -p %d -U %s -d %s\n", host, availablePort, cfg.User, cfg.Name)
 
-	return nil
-}
+	if validator, ok := engine.(ValidatingEngine); ok {
+		if err := validator.Validate(cfg); err != nil {
+			return err
+		}
+	}
 
-func (m *Manager) CreateMySQL(cfg *MySQLConfig) error {
 	if m.dockerService == nil {
 		return fmt.Errorf("docker service not available")
 	}
@@ -160,7 +168,7 @@ func (m *Manager) CreateMySQL(cfg *MySQLConfig) error {
 
 	port := cfg.Port
 	if port == 0 {
-		port = m.config.Default.MySQL.Port
+		port = defaults.Port
 	}
 
 	availablePort, err := m.dockerService.FindAvailablePort(port)
@@ -168,62 +176,90 @@ func (m *Manager) CreateMySQL(cfg *MySQLConfig) error {
 		return fmt.Errorf("failed to find available port: %w", err)
 	}
 
-	containerName := fmt.Sprintf("spindb-mysql-%s", cfg.Name)
-	image := fmt.Sprintf("mysql:%s", cfg.Version)
+	ports := map[string]string{
+		engine.ContainerPort(): strconv.Itoa(availablePort),
+	}
+
+	var namedPorts map[string]int
+	if multi, ok := engine.(MultiPortEngine); ok {
+		namedPorts = make(map[string]int)
+		taken := map[int]bool{availablePort: true}
+
+		for _, namedPort := range multi.NamedPorts(m.config) {
+			hostPort := availablePort
+			if namedPort.ContainerPort != engine.ContainerPort() {
+				hostPort, err = m.findUnusedPort(namedPort.DefaultPort, taken)
+				if err != nil {
+					return fmt.Errorf("failed to find available %s port: %w", namedPort.Name, err)
+				}
+				taken[hostPort] = true
+				ports[namedPort.ContainerPort] = strconv.Itoa(hostPort)
+			}
+			namedPorts[namedPort.Name] = hostPort
+		}
+	}
+
+	displayName := engine.DisplayName()
+	containerConfig := m.containerConfig(engine, cfg, ports)
+	containerName := containerConfig.Name
 
 	ctx := context.Background()
 
-	fmt.Printf("Pulling MySQL image %s...\n", image)
-	if err := m.dockerService.PullImage(ctx, image); err != nil {
+	fmt.Printf("Pulling %s image %s...\n", displayName, containerConfig.Image)
+	if err := m.dockerService.PullImage(ctx, containerConfig.Image); err != nil {
 		return fmt.Errorf("failed to pull image: %w", err)
 	}
 
-	dataDir := filepath.Join(m.config.Storage.DataDir, "mysql", cfg.Name)
 	if err := os.MkdirAll(dataDir, 0755); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	containerConfig := &docker.ContainerConfig{
-		Name:  containerName,
-		Image: image,
-		Env: []string{
-			fmt.Sprintf("MYSQL_DATABASE=%s", cfg.Name),
-			fmt.Sprintf("MYSQL_USER=%s", cfg.User),
-			fmt.Sprintf("MYSQL_PASSWORD=%s", cfg.Password),
-			fmt.Sprintf("MYSQL_ROOT_PASSWORD=%s", cfg.Password),
-		},
-		Ports: map[string]string{
-			"3306": strconv.Itoa(availablePort),
-		},
-		Volumes: []string{
-			docker.CreateVolumeMount(dataDir, "/var/lib/mysql"),
-		},
-		Public: cfg.Public,
+	// A failed create removes its container and the data directory it
+	// initialised, but never data that was there before.
+	cleanup := func(containerID string) {
+		if containerID != "" {
+			m.dockerService.RemoveContainer(ctx, containerID, true)
+		}
+		if !hasData {
+			m.dockerService.RemoveDataDir(ctx, containerConfig.Image, dataDir)
+		}
 	}
 
-	fmt.Printf("Creating MySQL container %s...\n", containerName)
+	if owner, ok := engine.(DataOwnerEngine); ok {
+		if uid := owner.DataDirOwner(cfg); uid != "" {
+			err := m.dockerService.RunTask(ctx, containerConfig.Image,
+				[]string{docker.CreateVolumeMount(dataDir, "/target")},
+				[]string{"chown", uid, "/target"},
+			)
+			if err != nil {
+				cleanup("")
+				return fmt.Errorf("failed to prepare data directory: %w", err)
+			}
+		}
+	}
+
+	fmt.Printf("Creating %s container %s...\n", displayName, containerName)
 	containerID, err := m.dockerService.CreateContainer(ctx, containerConfig)
 	if err != nil {
+		cleanup("")
 		return fmt.Errorf("failed to create container: %w", err)
 	}
 
-	fmt.Printf("Starting MySQL container...\n")
+	fmt.Printf("Starting %s container...\n", displayName)
 	if err := m.dockerService.StartContainer(ctx, containerID); err != nil {
-		m.dockerService.RemoveContainer(ctx, containerID, true)
+		cleanup(containerID)
 		return fmt.Errorf("failed to start container: %w", err)
 	}
 
-	fmt.Printf("Waiting for MySQL to be ready...\n")
-	dsn := fmt.Sprintf("%s:%s@tcp(localhost:%d)/%s", cfg.User, cfg.Password, availablePort, cfg.Name)
-	if err := m.connTester.WaitForDatabase("mysql", dsn, 60*time.Second); err != nil {
-		return fmt.Errorf("MySQL failed to start: %w", err)
-	}
-
 	dbConfig := &config.DatabaseConfig{
 		Name:        cfg.Name,
-		Type:        "mysql",
+		Type:        engine.Name(),
+		Database:    cfg.Database,
+		Parent:      cfg.Parent,
 		Version:     cfg.Version,
 		Port:        availablePort,
+		Ports:       namedPorts,
+		Extensions:  cfg.Extensions,
 		User:        cfg.User,
 		Password:    cfg.Password,
 		Public:      cfg.Public,
@@ -231,13 +267,34 @@ func (m *Manager) CreateMySQL(cfg *MySQLConfig) error {
 		Created:     time.Now(),
 	}
 
+	fmt.Printf("Waiting for %s to be ready...\n", displayName)
+	if err := WaitForEngine(engine, dbConfig, 60*time.Second); err != nil {
+		cleanup(containerID)
+		return fmt.Errorf("%s failed to start: %w", displayName, err)
+	}
+
+	if initializer, ok := engine.(InitializingEngine); ok {
+		fmt.Printf("Initializing %s database '%s'...\n", displayName, cfg.Name)
+		if err := initializer.Initialize(dbConfig); err != nil {
+			cleanup(containerID)
+			return fmt.Errorf("failed to initialize %s database: %w", displayName, err)
+		}
+	}
+
 	if err := m.store.Save(dbConfig); err != nil {
+		cleanup(containerID)
 		return fmt.Errorf("failed to save database config: %w", err)
 	}
 
-	fmt.Printf("✅ MySQL database '%s' created successfully!\n", cfg.Name)
+	fmt.Printf("✅ %s database '%s' created successfully!\n", displayName, cfg.Name)
 	fmt.Printf("   Container ID: %s\n", containerID[:12])
 	fmt.Printf("   Port: %d\n", availablePort)
+	if len(namedPorts) > 0 {
+		fmt.Printf("   Ports: %s\n", FormatNamedPorts(namedPorts))
+	}
+	if len(cfg.Extensions) > 0 {
+		fmt.Printf("   Extensions: %s\n", strings.Join(cfg.Extensions, ", "))
+	}
 	host := "localhost"
 	if cfg.Public {
 		host = "<your-server-ip>"
@@ -245,13 +302,16 @@ func (m *Manager) CreateMySQL(cfg *MySQLConfig) error {
 	} else {
 		fmt.Printf("   Public: No (localhost only)\n")
 	}
-	fmt.Printf("   Connection: mysql -h %s -P %d -u %s -p%s %s\n", host, availablePort, cfg.User, cfg.Password, cfg.Name)
+	if generatedPassword {
+		fmt.Printf("   Password: generated (run 'spindb info --name %s --show-credentials' to view it)\n", cfg.Name)
+	}
+	fmt.Printf("   Connection: %s\n", engine.Client(host, dbConfig))
 
 	return nil
 }
 
-func (m *Manager) CreateSQLite(cfg *SQLiteConfig) error {
-	fmt.Printf("Creating SQLite database: %s\n", cfg.FilePath)
+func (m *Manager) createFileDatabase(engine Engine, cfg *InstanceConfig) error {
+	fmt.Printf("Creating %s database: %s\n", engine.DisplayName(), cfg.FilePath)
 
 	dir := filepath.Dir(cfg.FilePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -260,94 +320,176 @@ func (m *Manager) CreateSQLite(cfg *SQLiteConfig) error {
 
 	file, err := os.Create(cfg.FilePath)
 	if err != nil {
-		return fmt.Errorf("failed to create SQLite file: %w", err)
+		return fmt.Errorf("failed to create %s file: %w", engine.DisplayName(), err)
 	}
 	file.Close()
 
-	if err := m.connTester.TestSQLite(cfg.FilePath); err != nil {
-		return fmt.Errorf("failed to test SQLite connection: %w", err)
-	}
-
 	dbConfig := &config.DatabaseConfig{
 		Name:     filepath.Base(cfg.FilePath),
-		Type:     "sqlite",
+		Type:     engine.Name(),
 		FilePath: cfg.FilePath,
 		Created:  time.Now(),
 	}
 
+	if err := engine.Ping(dbConfig); err != nil {
+		return fmt.Errorf("failed to test %s connection: %w", engine.DisplayName(), err)
+	}
+
 	if err := m.store.Save(dbConfig); err != nil {
 		return fmt.Errorf("failed to save database config: %w", err)
 	}
 
-	fmt.Printf("✅ SQLite database created successfully!\n")
+	fmt.Printf("✅ %s database created successfully!\n", engine.DisplayName())
 	fmt.Printf("   File: %s\n", cfg.FilePath)
-	fmt.Printf("   Connection: sqlite3 %s\n", cfg.FilePath)
+	fmt.Printf("   Connection: %s\n", engine.Client("localhost", dbConfig))
 
 	return nil
 }
 
-func (m *Manager) ListDatabases(dbType string) error {
-	databases, err := m.store.List(dbType)
-	if err != nil {
-		return fmt.Errorf("failed to load databases: %w", err)
+// containerConfig describes the container of an instance. The labels let
+// reconcile adopt the container if the registry entry is ever lost.
+func (m *Manager) containerConfig(engine Engine, cfg *InstanceConfig, ports map[string]string) *docker.ContainerConfig {
+	labels := map[string]string{
+		labelType:    engine.Name(),
+		labelName:    cfg.Name,
+		labelVersion: cfg.Version,
+		labelUser:    cfg.User,
+	}
+	if cfg.Database != "" {
+		labels[labelDatabase] = cfg.Database
+	}
+	if cfg.Parent != "" {
+		labels[labelParent] = cfg.Parent
+	}
+	if len(cfg.Extensions) > 0 {
+		labels[labelExtensions] = strings.Join(cfg.Extensions, ",")
+	}
+
+	containerConfig := &docker.ContainerConfig{
+		Name:  fmt.Sprintf("spindb-%s-%s", engine.Name(), cfg.Name),
+		Image: engine.Image(cfg.Version),
+		Env:   engine.Env(cfg),
+		Ports: ports,
+		Volumes: []string{
+			docker.CreateVolumeMount(m.dataDir(engine.Name(), cfg.Name), engine.DataMountPath()),
+		},
+		Labels: labels,
+		Public: cfg.Public,
+	}
+
+	if commander, ok := engine.(CommandEngine); ok {
+		containerConfig.Cmd = commander.Command(cfg)
+	}
+
+	if checker, ok := engine.(HealthcheckEngine); ok {
+		containerConfig.Healthcheck = checker.Healthcheck()
+	}
+
+	if customizer, ok := engine.(ContainerCustomizer); ok {
+		customizer.CustomizeContainer(containerConfig, cfg)
+	}
+
+	return containerConfig
+}
+
+// HasData reports whether the data directory of a Docker database, kept
+// from an earlier instance of the same name, already holds data.
+func (m *Manager) HasData(dbType, name string) bool {
+	hasData, _ := utils.DirHasData(m.dataDir(dbType, name))
+	return hasData
+}
+
+func (m *Manager) dataDir(dbType, name string) string {
+	return filepath.Join(m.config.Storage.DataDir, dbType, name)
+}
+
+func (m *Manager) findUnusedPort(basePort int, taken map[int]bool) (int, error) {
+	for port := basePort; port < basePort+100; port++ {
+		if taken[port] {
+			continue
+		}
+		available, err := m.dockerService.FindAvailablePort(port)
+		if err != nil {
+			return 0, err
+		}
+		if !taken[available] {
+			return available, nil
+		}
+		port = available
 	}
+	return 0, fmt.Errorf("no available port found starting from %d", basePort)
+}
+
+func uniqueExtensions(extensions []string) []string {
+	var unique []string
+	for _, extension := range extensions {
+		if !slices.Contains(unique, extension) {
+			unique = append(unique, extension)
+		}
+	}
+	return unique
+}
 
-	if len(databases) == 0 {
-		fmt.Println("No databases found.")
+func validateExtensions(engine Engine, extensions []string) error {
+	if len(extensions) == 0 {
 		return nil
 	}
 
-	fmt.Printf("SpinDB Databases:\n\n")
-	for _, db := range databases {
-		status := m.getStatus(&db)
-		fmt.Printf("📊 %s (%s)\n", db.Name, db.Type)
-		fmt.Printf("   Status: %s\n", status)
-		if db.Port > 0 {
-			fmt.Printf("   Port: %d\n", db.Port)
-			if db.Type != "sqlite" {
-				if db.Public {
-					fmt.Printf("   Access: Public\n")
-				} else {
-					fmt.Printf("   Access: Private\n")
-				}
+	extEngine, ok := engine.(ExtensionEngine)
+	if !ok {
+		return fmt.Errorf("%s does not support extensions", engine.DisplayName())
+	}
+
+	supported := extEngine.Extensions()
+	for _, extension := range extensions {
+		found := false
+		for _, name := range supported {
+			if name == extension {
+				found = true
+				break
 			}
 		}
-		if db.FilePath != "" {
-			fmt.Printf("   File: %s\n", db.FilePath)
+		if !found {
+			return fmt.Errorf("unsupported %s extension '%s' (supported: %s)",
+				engine.DisplayName(), extension, strings.Join(supported, ", "))
 		}
-		fmt.Printf("   Created: %s\n", db.Created.Format("2006-01-02 15:04:05"))
-		fmt.Println()
 	}
 
 	return nil
 }
 
-func (m *Manager) getStatus(db *config.DatabaseConfig) string {
-	if db.Type == "sqlite" {
-		if _, err := os.Stat(db.FilePath); err != nil {
-			return "❌ File not found"
-		}
-		return "✅ Available"
+func FormatNamedPorts(ports map[string]int) string {
+	var names []string
+	for name := range ports {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
-	if db.ContainerID == "" {
-		return "❓ Unknown"
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, ports[name]))
 	}
+	return strings.Join(parts, ", ")
+}
 
-	if m.dockerService == nil {
-		return "❓ Docker unavailable"
-	}
+func (m *Manager) isContainerized(dbType string) bool {
+	engine, err := GetEngine(dbType)
+	return err == nil && engine.Containerized()
+}
 
-	ctx := context.Background()
-	running, err := m.dockerService.IsContainerRunning(ctx, db.ContainerID)
+func (m *Manager) findDatabase(name string) (*config.DatabaseConfig, error) {
+	databases, err := m.store.List("")
 	if err != nil {
-		return "❌ Error checking status"
+		return nil, fmt.Errorf("failed to load databases: %w", err)
 	}
 
-	if running {
-		return "✅ Running"
+	for _, db := range databases {
+		if db.Name == name {
+			return &db, nil
+		}
 	}
-	return "⏸️ Stopped"
+
+	return nil, fmt.Errorf("database '%s' not found", name)
 }
 
 func (m *Manager) Connect(name string, testOnly bool) error {
@@ -376,27 +518,16 @@ func (m *Manager) Connect(name string, testOnly bool) error {
 }
 
 func (m *Manager) testConnection(db *config.DatabaseConfig) error {
+	engine, err := GetEngine(db.Type)
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("Testing connection to %s database '%s'...\n", db.Type, db.Name)
 
-	switch db.Type {
-	case "postgres":
-		err := m.connTester.TestPostgres("localhost", db.Port, db.User, db.Password, db.Name)
-		if err != nil {
-			fmt.Printf("❌ Connection failed: %v\n", err)
-			return err
-		}
-	case "mysql":
-		err := m.connTester.TestMySQL("localhost", db.Port, db.User, db.Password, db.Name)
-		if err != nil {
-			fmt.Printf("❌ Connection failed: %v\n", err)
-			return err
-		}
-	case "sqlite":
-		err := m.connTester.TestSQLite(db.FilePath)
-		if err != nil {
-			fmt.Printf("❌ Connection failed: %v\n", err)
-			return err
-		}
+	if err := engine.Ping(db); err != nil {
+		fmt.Printf("❌ Connection failed: %v\n", err)
+		return err
 	}
 
 	fmt.Printf("✅ Connection successful!\n")
@@ -404,53 +535,34 @@ func (m *Manager) testConnection(db *config.DatabaseConfig) error {
 }
 
 func (m *Manager) openConnection(db *config.DatabaseConfig) error {
+	engine, err := GetEngine(db.Type)
+	if err != nil {
+		return err
+	}
+
 	db.LastUsed = time.Now()
 	m.store.Save(db)
 
-	var cmd *exec.Cmd
-	var clientCmd string
-	var installInstructions string
-
-	switch db.Type {
-	case "postgres":
-		clientCmd = "psql"
-		installInstructions = m.getInstallInstructions("postgresql-client", "psql")
-		cmd = exec.Command("psql", "-h", "localhost", "-p", strconv.Itoa(db.Port), "-U", db.User, "-d", db.Name)
-		cmd.Env = append(os.Environ(), fmt.Sprintf("PGPASSWORD=%s", db.Password))
-	case "mysql":
-		clientCmd = "mysql"
-		installInstructions = m.getInstallInstructions("mysql-client", "mysql")
-		cmd = exec.Command("mysql", "-h", "localhost", "-P", strconv.Itoa(db.Port), "-u", db.User, fmt.Sprintf("-p%s", db.Password), db.Name)
-	case "sqlite":
-		clientCmd = "sqlite3"
-		installInstructions = m.getInstallInstructions("sqlite3", "sqlite3")
-		cmd = exec.Command("sqlite3", db.FilePath)
-	default:
-		return fmt.Errorf("unsupported database type: %s", db.Type)
-	}
-
-	if _, err := exec.LookPath(clientCmd); err != nil {
-		fmt.Printf("❌ Database client '%s' not found in PATH.\n\n", clientCmd)
+	client := engine.Client("localhost", db)
+
+	if _, err := exec.LookPath(client.Command); err != nil {
+		fmt.Printf("❌ Database client '%s' not found in PATH.\n\n", client.Command)
 		fmt.Printf("To connect interactively to your database, you need to install the client:\n\n")
-		fmt.Printf("%s\n\n", installInstructions)
+		fmt.Printf("%s\n\n", m.getInstallInstructions(client))
 		fmt.Printf("Alternatively, you can:\n")
 		fmt.Printf("• Use '--test-only' flag to test the connection without opening a shell\n")
-		fmt.Printf("• Use Docker to run the client:\n")
-
-		switch db.Type {
-		case "postgres":
-			fmt.Printf("  docker run -it --rm postgres:%s psql -h host.docker.internal -p %d -U %s -d %s\n",
-				db.Version, db.Port, db.User, db.Name)
-		case "mysql":
-			fmt.Printf("  docker run -it --rm mysql:%s mysql -h host.docker.internal -P %d -u %s -p%s %s\n",
-				db.Version, db.Port, db.User, db.Password, db.Name)
-		case "sqlite":
-			fmt.Printf("  docker run -it --rm -v %s:/db alpine sqlite3 /db\n", db.FilePath)
+		if client.DockerAlternative != "" {
+			fmt.Printf("• Use Docker to run the client:\n")
+			fmt.Printf("  %s\n", client.DockerAlternative)
 		}
 
-		return fmt.Errorf("client '%s' not available", clientCmd)
+		return fmt.Errorf("client '%s' not available", client.Command)
 	}
 
+	cmd := exec.Command(client.Command, client.Args...)
+	if len(client.Env) > 0 {
+		cmd.Env = append(os.Environ(), client.Env...)
+	}
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -459,94 +571,22 @@ func (m *Manager) openConnection(db *config.DatabaseConfig) error {
 	return cmd.Run()
 }
 
-func (m *Manager) getInstallInstructions(packageName, clientName string) string {
-	var instructions []string
-
-	instructions = append(instructions, fmt.Sprintf("Ubuntu/Debian: sudo apt update && sudo apt install %s", packageName))
-
-	switch clientName {
-	case "psql":
-		instructions = append(instructions, "CentOS/RHEL: sudo yum install postgresql")
-		instructions = append(instructions, "Fedora: sudo dnf install postgresql")
-		instructions = append(instructions, "macOS: brew install postgresql")
-		instructions = append(instructions, "Alpine: apk add postgresql-client")
-	case "mysql":
-		instructions = append(instructions, "CentOS/RHEL: sudo yum install mysql")
-		instructions = append(instructions, "Fedora: sudo dnf install mysql")
-		instructions = append(instructions, "macOS: brew install mysql-client")
-		instructions = append(instructions, "Alpine: apk add mysql-client")
-	case "sqlite3":
-		instructions = append(instructions, "CentOS/RHEL: sudo yum install sqlite")
-		instructions = append(instructions, "Fedora: sudo dnf install sqlite")
-		instructions = append(instructions, "macOS: brew install sqlite")
-		instructions = append(instructions, "Alpine: apk add sqlite")
-	}
-
+func (m *Manager) getInstallInstructions(client *Client) string {
 	result := ""
-	for _, instruction := range instructions {
+	for _, instruction := range client.InstallHints {
 		result += "• " + instruction + "\n"
 	}
 
 	return strings.TrimSuffix(result, "\n")
 }
 
-func (m *Manager) ShowInfo(name string, showCredentials bool) error {
-	databases, err := m.store.List("")
-	if err != nil {
-		return fmt.Errorf("failed to load databases: %w", err)
-	}
-
-	var targetDB *config.DatabaseConfig
-	for _, db := range databases {
-		if db.Name == name {
-			targetDB = &db
-			break
-		}
-	}
-
-	if targetDB == nil {
-		return fmt.Errorf("database '%s' not found", name)
-	}
-
-	fmt.Printf("Database Information: %s\n", targetDB.Name)
-	fmt.Printf("─────────────────────────────────────\n")
-	fmt.Printf("Type:         %s\n", targetDB.Type)
-	fmt.Printf("Version:      %s\n", targetDB.Version)
-	fmt.Printf("Status:       %s\n", m.getStatus(targetDB))
-	fmt.Printf("Created:      %s\n", targetDB.Created.Format("2006-01-02 15:04:05"))
-
-	if !targetDB.LastUsed.IsZero() {
-		fmt.Printf("Last Used:    %s\n", targetDB.LastUsed.Format("2006-01-02 15:04:05"))
-	}
-
-	if targetDB.Port > 0 {
-		fmt.Printf("Port:         %d\n", targetDB.Port)
-		if targetDB.Type != "sqlite" {
-			if targetDB.Public {
-				fmt.Printf("Access:       Public (externally accessible)\n")
-			} else {
-				fmt.Printf("Access:       Private (localhost only)\n")
-			}
-		}
-	}
-
-	if targetDB.FilePath != "" {
-		fmt.Printf("File Path:    %s\n", targetDB.FilePath)
-	}
-
-	if showCredentials && targetDB.Type != "sqlite" {
-		fmt.Printf("User:         %s\n", targetDB.User)
-		fmt.Printf("Password:     %s\n", targetDB.Password)
-	}
-
-	if targetDB.ContainerID != "" {
-		fmt.Printf("Container ID: %s\n", targetDB.ContainerID)
-	}
-
-	return nil
+func (m *Manager) Delete(name, file string, force bool) error {
+	return m.delete(name, force, m.store.Delete)
 }
 
-func (m *Manager) Delete(name, file string, force bool) error {
+// delete removes the container and registry entry of a database. unregister
+// decides whether the stored password goes too.
+func (m *Manager) delete(name string, force bool, unregister func(name, dbType string) error) error {
 	var targetDB *config.DatabaseConfig
 
 	if name != "" {
@@ -586,7 +626,7 @@ func (m *Manager) Delete(name, file string, force bool) error {
 			m.dockerService.RemoveContainer(ctx, targetDB.ContainerID, true)
 		}
 
-		if err := m.store.Delete(targetDB.Name, targetDB.Type); err != nil {
+		if err := unregister(targetDB.Name, targetDB.Type); err != nil {
 			return fmt.Errorf("failed to remove database config: %w", err)
 		}
 
@@ -596,6 +636,87 @@ func (m *Manager) Delete(name, file string, force bool) error {
 	return nil
 }
 
+// Remove deletes a database without asking for confirmation. With removeData
+// its data directory, or the file of a file-based database, goes too;
+// otherwise its password is kept, so a later create of the same name can
+// take the data directory over again.
+func (m *Manager) Remove(name string, removeData bool) error {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return err
+	}
+
+	engine, err := GetEngine(target.Type)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	image := engine.Image(target.Version)
+	if target.ContainerID != "" && m.dockerService != nil {
+		if container, err := m.dockerService.GetContainer(ctx, target.ContainerID); err == nil {
+			image = container.Config.Image
+		}
+	}
+
+	if !removeData {
+		return m.delete(name, true, m.store.Unregister)
+	}
+
+	if err := m.Delete(name, "", true); err != nil {
+		return err
+	}
+
+	if !engine.Containerized() {
+		if err := os.Remove(target.FilePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", target.FilePath, err)
+		}
+		return nil
+	}
+
+	if m.dockerService == nil {
+		return fmt.Errorf("docker service not available")
+	}
+
+	fmt.Printf("Removing data directory of '%s'...\n", name)
+	if err := m.dockerService.RemoveDataDir(ctx, image, m.dataDir(target.Type, target.Name)); err != nil {
+		return fmt.Errorf("failed to remove data directory: %w", err)
+	}
+
+	return nil
+}
+
+// RunScript runs a script file against the database with the engine's own
+// client, e.g. psql for PostgreSQL.
+func (m *Manager) RunScript(name, path string) error {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return err
+	}
+
+	engine, err := GetEngine(target.Type)
+	if err != nil {
+		return err
+	}
+
+	runner, ok := engine.(ScriptEngine)
+	if !ok {
+		return fmt.Errorf("running scripts is not supported for %s", engine.DisplayName())
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open script: %w", err)
+	}
+	defer file.Close()
+
+	if err := runner.RunScript(target, file); err != nil {
+		return fmt.Errorf("failed to run %s: %w", path, err)
+	}
+
+	return nil
+}
+
 func (m *Manager) Start(name string) error {
 	databases, err := m.store.List("")
 	if err != nil {
@@ -614,8 +735,13 @@ func (m *Manager) Start(name string) error {
 		return fmt.Errorf("database '%s' not found", name)
 	}
 
-	if targetDB.Type == "sqlite" {
-		return fmt.Errorf("SQLite databases don't need to be started")
+	engine, err := GetEngine(targetDB.Type)
+	if err != nil {
+		return err
+	}
+
+	if !engine.Containerized() {
+		return fmt.Errorf("%s databases don't need to be started", engine.DisplayName())
 	}
 
 	if targetDB.ContainerID == "" {
@@ -655,8 +781,13 @@ func (m *Manager) Stop(name string) error {
 		return fmt.Errorf("database '%s' not found", name)
 	}
 
-	if targetDB.Type == "sqlite" {
-		return fmt.Errorf("SQLite databases don't need to be stopped")
+	engine, err := GetEngine(targetDB.Type)
+	if err != nil {
+		return err
+	}
+
+	if !engine.Containerized() {
+		return fmt.Errorf("%s databases don't need to be stopped", engine.DisplayName())
 	}
 
 	if targetDB.ContainerID == "" {
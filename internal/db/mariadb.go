@@ -0,0 +1,139 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/awade12/spindb/internal/config"
+)
+
+type MariaDBConfig = InstanceConfig
+
+type mariadbEngine struct{}
+
+func init() {
+	RegisterEngine(&mariadbEngine{})
+}
+
+func (e *mariadbEngine) Name() string        { return "mariadb" }
+func (e *mariadbEngine) DisplayName() string { return "MariaDB" }
+func (e *mariadbEngine) Containerized() bool { return true }
+
+func (e *mariadbEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.MariaDB.Version,
+		Port:    cfg.Default.MariaDB.Port,
+		User:    cfg.Default.MariaDB.User,
+	}
+}
+
+func (e *mariadbEngine) Image(version string) string {
+	return fmt.Sprintf("mariadb:%s", version)
+}
+
+func (e *mariadbEngine) ContainerPort() string { return "3306" }
+func (e *mariadbEngine) DataMountPath() string { return "/var/lib/mysql" }
+
+func (e *mariadbEngine) Env(cfg *InstanceConfig) []string {
+	env := []string{
+		fmt.Sprintf("MARIADB_DATABASE=%s", cfg.DatabaseName()),
+		fmt.Sprintf("MARIADB_ROOT_PASSWORD=%s", cfg.Password),
+	}
+
+	// The entrypoint refuses MARIADB_USER=root; root already gets the password above.
+	if cfg.User != "root" {
+		env = append(env,
+			fmt.Sprintf("MARIADB_USER=%s", cfg.User),
+			fmt.Sprintf("MARIADB_PASSWORD=%s", cfg.Password),
+		)
+	}
+
+	return env
+}
+
+func (e *mariadbEngine) Healthcheck() []string {
+	return []string{"CMD", "healthcheck.sh", "--connect", "--innodb_initialized"}
+}
+
+func (e *mariadbEngine) DSN(db *config.DatabaseConfig) string {
+	return MySQLDSN("localhost", db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *mariadbEngine) URL(host string, db *config.DatabaseConfig) string {
+	return mysqlURL(host, db)
+}
+
+func (e *mariadbEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return mysqlEnvVars(host, db)
+}
+
+func (e *mariadbEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "mariadb",
+		Args:    []string{"-h", host, "-P", strconv.Itoa(db.Port), "-u", db.User, fmt.Sprintf("-p%s", db.Password), db.DatabaseName()},
+		InstallHints: []string{
+			"Ubuntu/Debian: sudo apt update && sudo apt install mariadb-client",
+			"CentOS/RHEL: sudo yum install mariadb",
+			"Fedora: sudo dnf install mariadb",
+			"macOS: brew install mariadb",
+			"Alpine: apk add mariadb-client",
+		},
+		DockerAlternative: fmt.Sprintf("docker run -it --rm %s mariadb -h host.docker.internal -P %d -u %s -p%s %s",
+			e.Image(db.Version), db.Port, db.User, db.Password, db.DatabaseName()),
+	}
+}
+
+func (e *mariadbEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestMariaDB("localhost", db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *mariadbEngine) RotatePassword(db *config.DatabaseConfig, password string) error {
+	return rotateMySQLPassword(e.DSN(db), db.User, password)
+}
+
+func (e *mariadbEngine) TableCounts(db *config.DatabaseConfig) (map[string]int64, error) {
+	return mysqlTableCounts(e.DSN(db))
+}
+
+func (e *mariadbEngine) Assert(db *config.DatabaseConfig, query string) (bool, error) {
+	return NewConnectionTester().QueryTruthy("mysql", e.DSN(db), query)
+}
+
+func (e *mariadbEngine) BackupExtension() string { return ".sql" }
+
+func (e *mariadbEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	cmd := []string{
+		"mariadb-dump",
+		"-h", "127.0.0.1",
+		"-u", db.User,
+		db.DatabaseName(),
+	}
+
+	if options != nil && options.SchemaOnly {
+		cmd = append(cmd, "--no-data")
+	}
+
+	if options != nil && options.DataOnly {
+		cmd = append(cmd, "--no-create-info")
+	}
+
+	return runDump(db, cmd, mysqlExecEnv(db), w)
+}
+
+func (e *mariadbEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"mariadb",
+		"-h", "127.0.0.1",
+		"-u", db.User,
+		db.DatabaseName(),
+	}
+
+	return runRestore(db, cmd, mysqlExecEnv(db), r)
+}
+
+// RunScript feeds the script to mariadb, which stops at the first failing
+// statement when reading from stdin.
+func (e *mariadbEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	return e.Restore(db, r)
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/awade12/spindb/internal/config"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type MongoConfig = InstanceConfig
+
+type mongoEngine struct{}
+
+func init() {
+	RegisterEngine(&mongoEngine{})
+}
+
+func (e *mongoEngine) Name() string        { return "mongo" }
+func (e *mongoEngine) DisplayName() string { return "MongoDB" }
+func (e *mongoEngine) Containerized() bool { return true }
+
+func (e *mongoEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.Mongo.Version,
+		Port:    cfg.Default.Mongo.Port,
+		User:    cfg.Default.Mongo.User,
+	}
+}
+
+func (e *mongoEngine) Image(version string) string {
+	return fmt.Sprintf("mongo:%s", version)
+}
+
+func (e *mongoEngine) ContainerPort() string { return "27017" }
+func (e *mongoEngine) DataMountPath() string { return "/data/db" }
+
+func (e *mongoEngine) Env(cfg *InstanceConfig) []string {
+	return []string{
+		fmt.Sprintf("MONGO_INITDB_ROOT_USERNAME=%s", cfg.User),
+		fmt.Sprintf("MONGO_INITDB_ROOT_PASSWORD=%s", cfg.Password),
+		fmt.Sprintf("MONGO_INITDB_DATABASE=%s", cfg.DatabaseName()),
+	}
+}
+
+func (e *mongoEngine) DSN(db *config.DatabaseConfig) string {
+	return mongoURI("localhost", db)
+}
+
+func (e *mongoEngine) URL(host string, db *config.DatabaseConfig) string {
+	return mongoURI(host, db)
+}
+
+func (e *mongoEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return []EnvVar{
+		{"MONGODB_URI", mongoURI(host, db)},
+		{"MONGODB_HOST", host},
+		{"MONGODB_PORT", strconv.Itoa(db.Port)},
+		{"MONGODB_USER", db.User},
+		{"MONGODB_PASSWORD", db.Password},
+		{"MONGODB_DATABASE", db.DatabaseName()},
+	}
+}
+
+func mongoURI(host string, db *config.DatabaseConfig) string {
+	return mongoURIWithDatabase(host, db, db.DatabaseName())
+}
+
+func mongoURIWithDatabase(host string, db *config.DatabaseConfig, database string) string {
+	u := &url.URL{
+		Scheme:   "mongodb",
+		User:     url.UserPassword(db.User, db.Password),
+		Host:     host + ":" + strconv.Itoa(db.Port),
+		Path:     "/" + database,
+		RawQuery: "authSource=admin&directConnection=true",
+	}
+	return u.String()
+}
+
+func (e *mongoEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "mongosh",
+		Args:    []string{mongoURI(host, db)},
+		InstallHints: []string{
+			"Ubuntu/Debian: install mongodb-mongosh from the MongoDB apt repository",
+			"CentOS/RHEL: sudo yum install mongodb-mongosh",
+			"Fedora: sudo dnf install mongodb-mongosh",
+			"macOS: brew install mongosh",
+			"npm: npm install -g mongosh",
+		},
+		DockerAlternative: fmt.Sprintf("docker run -it --rm %s mongosh \"%s\"",
+			e.Image(db.Version), mongoURI("host.docker.internal", db)),
+	}
+}
+
+func (e *mongoEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestMongo(e.DSN(db))
+}
+
+// The root user is created in the admin database by the image entrypoint.
+func (e *mongoEngine) RotatePassword(db *config.DatabaseConfig, password string) error {
+	command := bson.D{{Key: "updateUser", Value: db.User}, {Key: "pwd", Value: password}}
+	return NewConnectionTester().RunMongoCommand(mongoURIWithDatabase("localhost", db, "admin"), "admin", command)
+}
+
+func (e *mongoEngine) TableCounts(db *config.DatabaseConfig) (map[string]int64, error) {
+	return NewConnectionTester().MongoCollectionCounts(mongoURIWithDatabase("localhost", db, ""), db.DatabaseName())
+}
+
+func (e *mongoEngine) BackupExtension() string { return ".archive" }
+
+func (e *mongoEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	if options != nil && options.SchemaOnly {
+		return fmt.Errorf("schema-only backups are not supported for MongoDB")
+	}
+
+	cmd := []string{
+		"mongodump",
+		"--uri", e.containerURI(db),
+		"--db", db.DatabaseName(),
+		"--archive",
+		"--quiet",
+	}
+
+	return runDump(db, cmd, nil, w)
+}
+
+func (e *mongoEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"mongorestore",
+		"--uri", e.containerURI(db),
+		"--archive",
+		"--drop",
+		"--quiet",
+		"--nsFrom", "$source$.$collection$",
+		"--nsTo", db.DatabaseName() + ".$collection$",
+	}
+
+	return runRestore(db, cmd, nil, r)
+}
+
+// RunScript evaluates the script with mongosh against the instance's
+// database. An uncaught error in the script fails the run.
+func (e *mongoEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	inner := *db
+	inner.Port, _ = strconv.Atoi(e.ContainerPort())
+
+	cmd := []string{
+		"sh", "-c", `exec mongosh "$1" --quiet --eval "$(cat)"`, "sh",
+		mongoURIWithDatabase("localhost", &inner, db.DatabaseName()),
+	}
+
+	return runRestore(db, cmd, nil, r)
+}
+
+// containerURI addresses the server from inside its own container, where it
+// listens on the container port rather than the published one.
+func (e *mongoEngine) containerURI(db *config.DatabaseConfig) string {
+	inner := *db
+	inner.Port, _ = strconv.Atoi(e.ContainerPort())
+	return mongoURIWithDatabase("localhost", &inner, "")
+}
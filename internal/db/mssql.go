@@ -0,0 +1,146 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/docker"
+	"github.com/awade12/spindb/internal/utils"
+)
+
+type MSSQLConfig = InstanceConfig
+
+type mssqlEngine struct{}
+
+const mssqlSqlcmd = "/opt/mssql-tools18/bin/sqlcmd"
+
+func init() {
+	RegisterEngine(&mssqlEngine{})
+}
+
+func (e *mssqlEngine) Name() string        { return "mssql" }
+func (e *mssqlEngine) DisplayName() string { return "SQL Server" }
+func (e *mssqlEngine) Containerized() bool { return true }
+
+func (e *mssqlEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.MSSQL.Version,
+		Port:    cfg.Default.MSSQL.Port,
+		User:    cfg.Default.MSSQL.User,
+	}
+}
+
+func (e *mssqlEngine) Validate(cfg *InstanceConfig) error {
+	if cfg.User != "sa" {
+		return fmt.Errorf("SQL Server instances are managed through the 'sa' login, got user '%s'", cfg.User)
+	}
+	return utils.ValidateMSSQLPassword(cfg.Password)
+}
+
+func (e *mssqlEngine) Image(version string) string {
+	return fmt.Sprintf("mcr.microsoft.com/mssql/server:%s", version)
+}
+
+func (e *mssqlEngine) ContainerPort() string { return "1433" }
+func (e *mssqlEngine) DataMountPath() string { return "/var/opt/mssql" }
+
+func (e *mssqlEngine) Env(cfg *InstanceConfig) []string {
+	return []string{
+		"ACCEPT_EULA=Y",
+		"MSSQL_PID=Developer",
+		fmt.Sprintf("MSSQL_SA_PASSWORD=%s", cfg.Password),
+	}
+}
+
+// The server runs as a non-root user by default and cannot write to a bind
+// mounted data directory owned by the host user.
+func (e *mssqlEngine) CustomizeContainer(container *docker.ContainerConfig, cfg *InstanceConfig) {
+	container.User = "root"
+}
+
+func (e *mssqlEngine) DSN(db *config.DatabaseConfig) string {
+	return MSSQLDSN("localhost", db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *mssqlEngine) URL(host string, db *config.DatabaseConfig) string {
+	return MSSQLDSN(host, db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *mssqlEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return []EnvVar{
+		{"MSSQL_HOST", host},
+		{"MSSQL_PORT", strconv.Itoa(db.Port)},
+		{"MSSQL_USER", db.User},
+		{"MSSQL_PASSWORD", db.Password},
+		{"MSSQL_DATABASE", db.DatabaseName()},
+	}
+}
+
+func (e *mssqlEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "docker",
+		Args: []string{
+			"exec", "-it", shortContainerID(db.ContainerID),
+			mssqlSqlcmd,
+			"-S", "localhost",
+			"-U", db.User,
+			"-P", db.Password,
+			"-d", db.DatabaseName(),
+			"-C",
+		},
+		InstallHints: []string{
+			"sqlcmd runs inside the container, so only the Docker CLI is needed",
+			"See: https://docs.docker.com/get-docker/",
+		},
+	}
+}
+
+// Ping connects to master rather than the instance's database, because the
+// image does not create that database and readiness is checked before
+// Initialize runs.
+func (e *mssqlEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestMSSQL("localhost", db.Port, db.User, db.Password, "master")
+}
+
+func (e *mssqlEngine) Initialize(db *config.DatabaseConfig) error {
+	dsn := MSSQLDSN("localhost", db.Port, db.User, db.Password, "master")
+	query := "DECLARE @sql nvarchar(max) = N'CREATE DATABASE ' + QUOTENAME(@p1); IF DB_ID(@p1) IS NULL EXEC(@sql)"
+
+	return NewConnectionTester().Exec("sqlserver", dsn, query, db.DatabaseName())
+}
+
+func (e *mssqlEngine) RotatePassword(db *config.DatabaseConfig, password string) error {
+	dsn := MSSQLDSN("localhost", db.Port, db.User, db.Password, "master")
+	query := "DECLARE @sql nvarchar(max) = N'ALTER LOGIN ' + QUOTENAME(@p1) + N' WITH PASSWORD = ' + QUOTENAME(@p2, ''''); EXEC(@sql)"
+
+	return NewConnectionTester().Exec("sqlserver", dsn, query, db.User, password)
+}
+
+func (e *mssqlEngine) BackupExtension() string { return ".bak" }
+
+func (e *mssqlEngine) dumpless() {}
+
+// RunScript runs the script with sqlcmd, which reads batches from stdin and
+// with -b exits non-zero on the first error.
+func (e *mssqlEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		mssqlSqlcmd,
+		"-S", "localhost",
+		"-U", db.User,
+		"-d", db.DatabaseName(),
+		"-C",
+		"-b",
+	}
+
+	return runRestore(db, cmd, []string{fmt.Sprintf("SQLCMDPASSWORD=%s", db.Password)}, r)
+}
+
+func (e *mssqlEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	return fmt.Errorf("backups are not supported for SQL Server")
+}
+
+func (e *mssqlEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	return fmt.Errorf("restores are not supported for SQL Server")
+}
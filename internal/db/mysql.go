@@ -1,10 +1,183 @@
 package db
 
-type MySQLConfig struct {
-	Name     string
-	User     string
-	Password string
-	Port     int
-	Version  string
-	Public   bool
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/awade12/spindb/internal/config"
+)
+
+type MySQLConfig = InstanceConfig
+
+type mysqlEngine struct{}
+
+func init() {
+	RegisterEngine(&mysqlEngine{})
+}
+
+func (e *mysqlEngine) Name() string        { return "mysql" }
+func (e *mysqlEngine) DisplayName() string { return "MySQL" }
+func (e *mysqlEngine) Containerized() bool { return true }
+
+func (e *mysqlEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.MySQL.Version,
+		Port:    cfg.Default.MySQL.Port,
+		User:    cfg.Default.MySQL.User,
+	}
+}
+
+func (e *mysqlEngine) Image(version string) string {
+	return fmt.Sprintf("mysql:%s", version)
+}
+
+func (e *mysqlEngine) ContainerPort() string { return "3306" }
+func (e *mysqlEngine) DataMountPath() string { return "/var/lib/mysql" }
+
+func (e *mysqlEngine) Env(cfg *InstanceConfig) []string {
+	return []string{
+		fmt.Sprintf("MYSQL_DATABASE=%s", cfg.DatabaseName()),
+		fmt.Sprintf("MYSQL_USER=%s", cfg.User),
+		fmt.Sprintf("MYSQL_PASSWORD=%s", cfg.Password),
+		fmt.Sprintf("MYSQL_ROOT_PASSWORD=%s", cfg.Password),
+	}
+}
+
+func (e *mysqlEngine) DSN(db *config.DatabaseConfig) string {
+	return MySQLDSN("localhost", db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *mysqlEngine) URL(host string, db *config.DatabaseConfig) string {
+	return mysqlURL(host, db)
+}
+
+func (e *mysqlEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return mysqlEnvVars(host, db)
+}
+
+// mysqlURL is shared with MariaDB, whose clients and ORMs accept the same
+// mysql:// scheme.
+func mysqlURL(host string, db *config.DatabaseConfig) string {
+	u := &url.URL{
+		Scheme: "mysql",
+		User:   url.UserPassword(db.User, db.Password),
+		Host:   net.JoinHostPort(host, strconv.Itoa(db.Port)),
+		Path:   "/" + db.DatabaseName(),
+	}
+	return u.String()
+}
+
+func mysqlEnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return []EnvVar{
+		{"MYSQL_HOST", host},
+		{"MYSQL_PORT", strconv.Itoa(db.Port)},
+		{"MYSQL_USER", db.User},
+		{"MYSQL_PASSWORD", db.Password},
+		{"MYSQL_DATABASE", db.DatabaseName()},
+	}
+}
+
+func (e *mysqlEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "mysql",
+		Args:    []string{"-h", host, "-P", strconv.Itoa(db.Port), "-u", db.User, fmt.Sprintf("-p%s", db.Password), db.DatabaseName()},
+		InstallHints: []string{
+			"Ubuntu/Debian: sudo apt update && sudo apt install mysql-client",
+			"CentOS/RHEL: sudo yum install mysql",
+			"Fedora: sudo dnf install mysql",
+			"macOS: brew install mysql-client",
+			"Alpine: apk add mysql-client",
+		},
+		DockerAlternative: fmt.Sprintf("docker run -it --rm %s mysql -h host.docker.internal -P %d -u %s -p%s %s",
+			e.Image(db.Version), db.Port, db.User, db.Password, db.DatabaseName()),
+	}
+}
+
+func (e *mysqlEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestMySQL("localhost", db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *mysqlEngine) RotatePassword(db *config.DatabaseConfig, password string) error {
+	return rotateMySQLPassword(e.DSN(db), db.User, password)
+}
+
+// rotateMySQLPassword changes the password of the connected account. The
+// images create root for both '%' and 'localhost', so both are kept in step;
+// other users only exist for '%'.
+func rotateMySQLPassword(dsn, user, password string) error {
+	literal := mysqlQuoteString(password)
+	query := fmt.Sprintf("ALTER USER CURRENT_USER() IDENTIFIED BY %s", literal)
+	if user == "root" {
+		query = fmt.Sprintf("ALTER USER 'root'@'%%' IDENTIFIED BY %s, 'root'@'localhost' IDENTIFIED BY %s", literal, literal)
+	}
+
+	return NewConnectionTester().Exec("mysql", dsn, query)
+}
+
+func mysqlQuoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func (e *mysqlEngine) BackupExtension() string { return ".sql" }
+
+func (e *mysqlEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	cmd := []string{
+		"mysqldump",
+		"-h", "127.0.0.1",
+		"-u", db.User,
+		db.DatabaseName(),
+	}
+
+	if options != nil && options.SchemaOnly {
+		cmd = append(cmd, "--no-data")
+	}
+
+	if options != nil && options.DataOnly {
+		cmd = append(cmd, "--no-create-info")
+	}
+
+	return runDump(db, cmd, mysqlExecEnv(db), w)
+}
+
+func (e *mysqlEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"mysql",
+		"-h", "127.0.0.1",
+		"-u", db.User,
+		db.DatabaseName(),
+	}
+
+	return runRestore(db, cmd, mysqlExecEnv(db), r)
+}
+
+// RunScript feeds the script to mysql, which stops at the first failing
+// statement when reading from stdin.
+func (e *mysqlEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	return e.Restore(db, r)
+}
+
+func (e *mysqlEngine) TableCounts(db *config.DatabaseConfig) (map[string]int64, error) {
+	return mysqlTableCounts(e.DSN(db))
+}
+
+func (e *mysqlEngine) Assert(db *config.DatabaseConfig, query string) (bool, error) {
+	return NewConnectionTester().QueryTruthy("mysql", e.DSN(db), query)
+}
+
+func mysqlTableCounts(dsn string) (map[string]int64, error) {
+	query := "SELECT table_name FROM information_schema.tables " +
+		"WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY 1"
+	return NewConnectionTester().TableCounts("mysql", dsn, query, func(table string) string {
+		return "`" + strings.ReplaceAll(table, "`", "``") + "`"
+	})
+}
+
+// mysqlExecEnv passes the password through MYSQL_PWD, which both the MySQL
+// and MariaDB clients read, instead of on the command line.
+func mysqlExecEnv(db *config.DatabaseConfig) []string {
+	return []string{fmt.Sprintf("MYSQL_PWD=%s", db.Password)}
 }
@@ -1,10 +1,253 @@
 package db
 
-type PostgresConfig struct {
-	Name     string
-	User     string
-	Password string
-	Port     int
-	Version  string
-	Public   bool
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/docker"
+	"github.com/lib/pq"
+)
+
+type PostgresConfig = InstanceConfig
+
+type postgresEngine struct{}
+
+func init() {
+	RegisterEngine(&postgresEngine{})
+}
+
+func (e *postgresEngine) Name() string        { return "postgres" }
+func (e *postgresEngine) DisplayName() string { return "PostgreSQL" }
+func (e *postgresEngine) Containerized() bool { return true }
+
+func (e *postgresEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.Postgres.Version,
+		Port:    cfg.Default.Postgres.Port,
+		User:    cfg.Default.Postgres.User,
+	}
+}
+
+func (e *postgresEngine) Image(version string) string {
+	return fmt.Sprintf("postgres:%s", version)
+}
+
+func (e *postgresEngine) Extensions() []string {
+	return []string{"postgis", "timescaledb", "vector"}
+}
+
+// haImageOwner is the uid:gid the TimescaleDB HA image runs postgres as.
+const haImageOwner = "1000:1000"
+
+// Validate rejects extension images for versions that have no matching
+// variant tag, such as "latest", which would otherwise yield "pglatest".
+func (e *postgresEngine) Validate(cfg *InstanceConfig) error {
+	if len(cfg.Extensions) == 0 {
+		return nil
+	}
+
+	if _, err := strconv.Atoi(postgresMajor(cfg.Version)); err != nil {
+		return fmt.Errorf("extensions need a numeric PostgreSQL version such as 16, got '%s'", cfg.Version)
+	}
+	return nil
+}
+
+// CustomizeContainer swaps the stock image for a variant that ships the
+// requested extensions. The TimescaleDB HA image bundles all three, so it is
+// used whenever more than one extension is needed.
+func (e *postgresEngine) CustomizeContainer(container *docker.ContainerConfig, cfg *InstanceConfig) {
+	if len(cfg.Extensions) == 0 {
+		return
+	}
+
+	major := postgresMajor(cfg.Version)
+
+	if usesHAImage(cfg) {
+		// The HA image keeps its cluster under /home/postgres by default, so
+		// point it at the bind mount or the data would not persist.
+		container.Image = fmt.Sprintf("timescale/timescaledb-ha:pg%s", major)
+		container.Env = append(container.Env, fmt.Sprintf("PGDATA=%s", e.DataMountPath()))
+		return
+	}
+
+	switch cfg.Extensions[0] {
+	case "postgis":
+		container.Image = fmt.Sprintf("postgis/postgis:%s-3.4", major)
+	case "vector":
+		container.Image = fmt.Sprintf("pgvector/pgvector:pg%s", major)
+	case "timescaledb":
+		container.Image = fmt.Sprintf("timescale/timescaledb:latest-pg%s", major)
+	}
+}
+
+// DataDirOwner hands the data directory to the HA image's postgres user,
+// which unlike the stock entrypoint never starts as root to chown it.
+func (e *postgresEngine) DataDirOwner(cfg *InstanceConfig) string {
+	if usesHAImage(cfg) {
+		return haImageOwner
+	}
+	return ""
+}
+
+func usesHAImage(cfg *InstanceConfig) bool {
+	return len(cfg.Extensions) > 1
+}
+
+func postgresMajor(version string) string {
+	return strings.SplitN(version, ".", 2)[0]
+}
+
+func (e *postgresEngine) Initialize(db *config.DatabaseConfig) error {
+	for _, extension := range db.Extensions {
+		fmt.Printf("Enabling extension %s...\n", extension)
+		query := fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", pq.QuoteIdentifier(extension))
+		if err := NewConnectionTester().Exec("postgres", e.DSN(db), query); err != nil {
+			return fmt.Errorf("failed to enable extension %s: %w", extension, err)
+		}
+	}
+	return nil
+}
+
+func (e *postgresEngine) RotatePassword(db *config.DatabaseConfig, password string) error {
+	query := fmt.Sprintf("ALTER USER %s WITH PASSWORD %s", pq.QuoteIdentifier(db.User), pq.QuoteLiteral(password))
+	return NewConnectionTester().Exec("postgres", e.DSN(db), query)
+}
+
+func (e *postgresEngine) ContainerPort() string { return "5432" }
+func (e *postgresEngine) DataMountPath() string { return "/var/lib/postgresql/data" }
+
+func (e *postgresEngine) Env(cfg *InstanceConfig) []string {
+	return []string{
+		fmt.Sprintf("POSTGRES_DB=%s", cfg.DatabaseName()),
+		fmt.Sprintf("POSTGRES_USER=%s", cfg.User),
+		fmt.Sprintf("POSTGRES_PASSWORD=%s", cfg.Password),
+	}
+}
+
+func (e *postgresEngine) DSN(db *config.DatabaseConfig) string {
+	return fmt.Sprintf("host=localhost port=%d user=%s password=%s dbname=%s sslmode=disable",
+		db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *postgresEngine) URL(host string, db *config.DatabaseConfig) string {
+	u := &url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(db.User, db.Password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(db.Port)),
+		Path:     "/" + db.DatabaseName(),
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
+
+func (e *postgresEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return []EnvVar{
+		{"PGHOST", host},
+		{"PGPORT", strconv.Itoa(db.Port)},
+		{"PGUSER", db.User},
+		{"PGPASSWORD", db.Password},
+		{"PGDATABASE", db.DatabaseName()},
+	}
+}
+
+func (e *postgresEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "psql",
+		Args:    []string{"-h", host, "-p", strconv.Itoa(db.Port), "-U", db.User, "-d", db.DatabaseName()},
+		Env:     []string{fmt.Sprintf("PGPASSWORD=%s", db.Password)},
+		InstallHints: []string{
+			"Ubuntu/Debian: sudo apt update && sudo apt install postgresql-client",
+			"CentOS/RHEL: sudo yum install postgresql",
+			"Fedora: sudo dnf install postgresql",
+			"macOS: brew install postgresql",
+			"Alpine: apk add postgresql-client",
+		},
+		DockerAlternative: fmt.Sprintf("docker run -it --rm %s psql -h host.docker.internal -p %d -U %s -d %s",
+			e.Image(db.Version), db.Port, db.User, db.DatabaseName()),
+	}
+}
+
+func (e *postgresEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestPostgres("localhost", db.Port, db.User, db.Password, db.DatabaseName())
+}
+
+func (e *postgresEngine) BackupExtension() string { return ".sql" }
+
+func (e *postgresEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	cmd := []string{
+		"pg_dump",
+		"-h", "localhost",
+		"-U", db.User,
+		"-d", db.DatabaseName(),
+		"--no-password",
+	}
+
+	if options != nil && options.SchemaOnly {
+		cmd = append(cmd, "--schema-only")
+	}
+
+	if options != nil && options.DataOnly {
+		cmd = append(cmd, "--data-only")
+	}
+
+	return runDump(db, cmd, postgresExecEnv(db), w)
+}
+
+func (e *postgresEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"psql",
+		"-h", "localhost",
+		"-U", db.User,
+		"-d", db.DatabaseName(),
+		"--no-password",
+	}
+
+	return runRestore(db, cmd, postgresExecEnv(db), r)
+}
+
+func (e *postgresEngine) RestoreStrict(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"psql",
+		"-h", "localhost",
+		"-U", db.User,
+		"-d", db.DatabaseName(),
+		"--no-password",
+		"-v", "ON_ERROR_STOP=1",
+		"--single-transaction",
+	}
+
+	return runRestore(db, cmd, postgresExecEnv(db), r)
+}
+
+func (e *postgresEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	cmd := []string{
+		"psql",
+		"-h", "localhost",
+		"-U", db.User,
+		"-d", db.DatabaseName(),
+		"--no-password",
+		"-v", "ON_ERROR_STOP=1",
+		"-f", "-",
+	}
+
+	return runRestore(db, cmd, postgresExecEnv(db), r)
+}
+
+func (e *postgresEngine) TableCounts(db *config.DatabaseConfig) (map[string]int64, error) {
+	query := "SELECT quote_ident(schemaname) || '.' || quote_ident(tablename) FROM pg_tables " +
+		"WHERE schemaname NOT IN ('pg_catalog', 'information_schema') ORDER BY 1"
+	return NewConnectionTester().TableCounts("postgres", e.DSN(db), query, func(table string) string { return table })
+}
+
+func (e *postgresEngine) Assert(db *config.DatabaseConfig, query string) (bool, error) {
+	return NewConnectionTester().QueryTruthy("postgres", e.DSN(db), query)
+}
+
+func postgresExecEnv(db *config.DatabaseConfig) []string {
+	return []string{fmt.Sprintf("PGPASSWORD=%s", db.Password)}
 }
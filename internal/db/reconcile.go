@@ -0,0 +1,493 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/docker"
+	"github.com/awade12/spindb/internal/secrets"
+	"github.com/awade12/spindb/internal/utils"
+	"github.com/docker/docker/api/types"
+)
+
+const (
+	labelType       = "spindb.type"
+	labelName       = "spindb.name"
+	labelVersion    = "spindb.version"
+	labelUser       = "spindb.user"
+	labelDatabase   = "spindb.database"
+	labelParent     = "spindb.parent"
+	labelExtensions = "spindb.extensions"
+)
+
+const (
+	IssueMissingContainer      = "missing-container"
+	IssueMissingData           = "missing-data"
+	IssueMissingFile           = "missing-file"
+	IssueUnregisteredContainer = "unregistered-container"
+	IssueOrphanedData          = "orphaned-data"
+)
+
+const (
+	FixRecreate = "recreate"
+	FixAdopt    = "adopt"
+	FixPrune    = "prune"
+	// FixRemoveData deletes a data directory no database uses. delete and
+	// down keep data on purpose, so it is never part of a plain prune.
+	FixRemoveData = "remove-data"
+)
+
+// Issue is a mismatch between the registry, the spindb=true containers and
+// the data directories. Fix names the action Reconcile can take, if any.
+type Issue struct {
+	Kind        string `json:"kind" yaml:"kind"`
+	Database    string `json:"database,omitempty" yaml:"database,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	ContainerID string `json:"container_id,omitempty" yaml:"container_id,omitempty"`
+	Path        string `json:"path,omitempty" yaml:"path,omitempty"`
+	Detail      string `json:"detail" yaml:"detail"`
+	Fix         string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// Doctor cross-checks the registry against Docker and the data directories
+// without changing anything.
+func (m *Manager) Doctor() ([]Issue, error) {
+	if m.dockerService == nil {
+		return nil, fmt.Errorf("docker service not available")
+	}
+
+	if err := m.dockerService.IsDockerRunning(); err != nil {
+		return nil, fmt.Errorf("docker is not running: %w", err)
+	}
+
+	databases, err := m.store.List("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load databases: %w", err)
+	}
+
+	containers, err := m.dockerService.ListSpinDBContainers(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*types.ContainerJSON)
+	for i := range containers {
+		byID[containers[i].ID] = &containers[i]
+	}
+
+	issues := []Issue{}
+	registered := make(map[string]bool)
+	knownDirs := make(map[string]bool)
+	lost := make(map[string]int)
+
+	for _, db := range databases {
+		engine, err := GetEngine(db.Type)
+		if err != nil {
+			continue
+		}
+
+		if !engine.Containerized() {
+			if _, err := os.Stat(db.FilePath); err != nil {
+				issues = append(issues, Issue{
+					Kind:     IssueMissingFile,
+					Database: db.Name,
+					Type:     db.Type,
+					Path:     db.FilePath,
+					Detail:   "database file does not exist",
+					Fix:      FixPrune,
+				})
+			}
+			continue
+		}
+
+		dataDir := m.dataDir(db.Type, db.Name)
+		knownDirs[dataDir] = true
+		_, statErr := os.Stat(dataDir)
+		hasData := statErr == nil
+
+		container, ok := byID[db.ContainerID]
+		if !ok {
+			issue := Issue{
+				Kind:        IssueMissingContainer,
+				Database:    db.Name,
+				Type:        db.Type,
+				ContainerID: shortContainerID(db.ContainerID),
+				Path:        dataDir,
+			}
+			if hasData {
+				issue.Detail = "container no longer exists, data directory is preserved"
+				issue.Fix = FixRecreate
+			} else {
+				issue.Detail = "container and data directory no longer exist"
+				issue.Fix = FixPrune
+			}
+			lost[db.Name] = len(issues)
+			issues = append(issues, issue)
+			continue
+		}
+
+		registered[container.ID] = true
+		if !hasData {
+			issues = append(issues, Issue{
+				Kind:        IssueMissingData,
+				Database:    db.Name,
+				Type:        db.Type,
+				ContainerID: shortContainerID(container.ID),
+				Path:        dataDir,
+				Detail:      "data directory is missing, the container is running on an empty bind mount",
+			})
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, db := range databases {
+		names[db.Name] = true
+	}
+
+	for i := range containers {
+		container := &containers[i]
+		if registered[container.ID] {
+			continue
+		}
+
+		dbType, name := containerIdentity(container)
+		issue := Issue{
+			Kind:        IssueUnregisteredContainer,
+			Database:    name,
+			Type:        dbType,
+			ContainerID: shortContainerID(container.ID),
+			Fix:         FixPrune,
+		}
+
+		engine, err := GetEngine(dbType)
+		switch {
+		case err != nil || name == "":
+			issue.Detail = "container is not in the registry and its database cannot be identified"
+		case names[name] && lostContainer(issues, lost, name, dbType):
+			// The registry entry points at a container ID that is gone, so
+			// this container is its replacement rather than a stray.
+			i := lost[name]
+			issues[i].Kind = IssueUnregisteredContainer
+			issues[i].ContainerID = issue.ContainerID
+			issues[i].Detail = fmt.Sprintf("registry points at a missing container, %s belongs to this database", issue.ContainerID)
+			issues[i].Fix = FixAdopt
+			delete(lost, name)
+			continue
+		case names[name]:
+			issue.Detail = fmt.Sprintf("container is not in the registry, which already has a database named '%s'", name)
+		default:
+			issue.Detail = "container is not in the registry"
+			issue.Fix = FixAdopt
+			if source := dataMountSource(engine, container); source != "" {
+				issue.Path = source
+				knownDirs[source] = true
+			}
+		}
+		issues = append(issues, issue)
+	}
+
+	for _, engine := range Engines() {
+		if !engine.Containerized() {
+			continue
+		}
+
+		entries, err := os.ReadDir(filepath.Join(m.config.Storage.DataDir, engine.Name()))
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			path := filepath.Join(m.config.Storage.DataDir, engine.Name(), entry.Name())
+			if !entry.IsDir() || knownDirs[path] || utils.ValidateDatabaseName(entry.Name()) != nil {
+				continue
+			}
+			issues = append(issues, Issue{
+				Kind:     IssueOrphanedData,
+				Database: entry.Name(),
+				Type:     engine.Name(),
+				Path:     path,
+				Detail:   "data directory belongs to no registered database or container (delete and down keep it, create the same name to reuse it)",
+				Fix:      FixRemoveData,
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Database < issues[j].Database
+	})
+
+	return issues, nil
+}
+
+// Reconcile applies the fix of one issue reported by Doctor.
+func (m *Manager) Reconcile(issue Issue) error {
+	switch {
+	case issue.Fix == FixRecreate:
+		return m.recreateContainer(issue.Database)
+	case issue.Fix == FixAdopt:
+		return m.adoptContainer(issue.ContainerID)
+	case issue.Fix == FixPrune && issue.Kind == IssueUnregisteredContainer:
+		fmt.Printf("Removing container %s...\n", issue.ContainerID)
+		return m.dockerService.RemoveContainer(context.Background(), issue.ContainerID, true)
+	case issue.Fix == FixRemoveData:
+		engine, err := GetEngine(issue.Type)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removing %s...\n", issue.Path)
+		return m.dockerService.RemoveDataDir(context.Background(), engine.Image(engine.Defaults(m.config).Version), issue.Path)
+	case issue.Fix == FixPrune:
+		fmt.Printf("Removing '%s' from the registry...\n", issue.Database)
+		return m.store.Delete(issue.Database, issue.Type)
+	default:
+		return fmt.Errorf("no automatic fix for %s", issue.Kind)
+	}
+}
+
+// recreateContainer builds a fresh container for a registered database on
+// top of its preserved data directory, keeping its ports and settings.
+func (m *Manager) recreateContainer(name string) error {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return err
+	}
+
+	engine, err := GetEngine(target.Type)
+	if err != nil {
+		return err
+	}
+
+	cfg := &InstanceConfig{
+		Name:       target.Name,
+		Database:   target.Database,
+		Parent:     target.Parent,
+		User:       target.User,
+		Password:   target.Password,
+		Version:    target.Version,
+		Public:     target.Public,
+		Extensions: target.Extensions,
+	}
+
+	ports := map[string]string{
+		engine.ContainerPort(): fmt.Sprint(target.Port),
+	}
+	if multi, ok := engine.(MultiPortEngine); ok {
+		for _, namedPort := range multi.NamedPorts(m.config) {
+			if port, ok := target.Ports[namedPort.Name]; ok && namedPort.ContainerPort != engine.ContainerPort() {
+				ports[namedPort.ContainerPort] = fmt.Sprint(port)
+			}
+		}
+	}
+
+	containerConfig := m.containerConfig(engine, cfg, ports)
+	ctx := context.Background()
+
+	if existing, err := m.dockerService.GetContainer(ctx, containerConfig.Name); err == nil {
+		return fmt.Errorf("container %s already exists (%s), adopt or prune it first", containerConfig.Name, shortContainerID(existing.ID))
+	}
+
+	fmt.Printf("Pulling %s image %s...\n", engine.DisplayName(), containerConfig.Image)
+	if err := m.dockerService.PullImage(ctx, containerConfig.Image); err != nil {
+		return fmt.Errorf("failed to pull image: %w", err)
+	}
+
+	fmt.Printf("Re-creating container %s from %s...\n", containerConfig.Name, m.dataDir(target.Type, target.Name))
+	containerID, err := m.dockerService.CreateContainer(ctx, containerConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+
+	if err := m.dockerService.StartContainer(ctx, containerID); err != nil {
+		m.dockerService.RemoveContainer(ctx, containerID, true)
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+
+	target.ContainerID = containerID
+	if err := m.store.Save(target); err != nil {
+		return fmt.Errorf("failed to save database config: %w", err)
+	}
+
+	fmt.Printf("✅ Container for '%s' re-created (%s)\n", name, shortContainerID(containerID))
+	return nil
+}
+
+// adoptContainer registers a spindb=true container that the registry lost,
+// reading its settings back from labels, port bindings and environment.
+func (m *Manager) adoptContainer(containerID string) error {
+	ctx := context.Background()
+	container, err := m.dockerService.GetContainer(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	dbType, name := containerIdentity(container)
+	engine, err := GetEngine(dbType)
+	if err != nil {
+		return err
+	}
+
+	if existing, err := m.findDatabase(name); err == nil {
+		if existing.Type != engine.Name() {
+			return fmt.Errorf("database '%s' is already registered as %s", name, existing.Type)
+		}
+
+		existing.ContainerID = container.ID
+		if err := m.store.Save(existing); err != nil {
+			return fmt.Errorf("failed to save database config: %w", err)
+		}
+
+		fmt.Printf("✅ Database '%s' relinked to container %s\n", name, shortContainerID(container.ID))
+		return nil
+	}
+
+	labels := container.Config.Labels
+	dbConfig := &config.DatabaseConfig{
+		Name:        name,
+		Type:        engine.Name(),
+		Database:    labels[labelDatabase],
+		Parent:      labels[labelParent],
+		Version:     labels[labelVersion],
+		User:        labels[labelUser],
+		ContainerID: container.ID,
+		Created:     time.Now(),
+	}
+
+	if created, err := time.Parse(time.RFC3339Nano, container.Created); err == nil {
+		dbConfig.Created = created
+	}
+
+	if dbConfig.Version == "" {
+		if i := strings.LastIndex(container.Config.Image, ":"); i >= 0 {
+			dbConfig.Version = container.Config.Image[i+1:]
+		}
+	}
+
+	if extensions := labels[labelExtensions]; extensions != "" {
+		dbConfig.Extensions = strings.Split(extensions, ",")
+	}
+
+	port, public, ok := docker.PublishedPort(container, engine.ContainerPort())
+	if !ok {
+		return fmt.Errorf("container %s does not publish port %s", shortContainerID(container.ID), engine.ContainerPort())
+	}
+	dbConfig.Port = port
+	dbConfig.Public = public
+
+	if multi, ok := engine.(MultiPortEngine); ok {
+		dbConfig.Ports = make(map[string]int)
+		for _, namedPort := range multi.NamedPorts(m.config) {
+			if hostPort, _, ok := docker.PublishedPort(container, namedPort.ContainerPort); ok {
+				dbConfig.Ports[namedPort.Name] = hostPort
+			}
+		}
+	}
+
+	if dbConfig.User == "" {
+		dbConfig.User = recoverSetting(engine, container, func(cfg *InstanceConfig, probe string) { cfg.User = probe })
+	}
+	if dbConfig.User == "" {
+		dbConfig.User = engine.Defaults(m.config).User
+	}
+
+	// A password rotated after creation only lives in the secrets store;
+	// the container environment still holds the one it was created with.
+	dbConfig.Password = m.storedPassword(dbConfig)
+	if dbConfig.Password == "" {
+		dbConfig.Password = recoverSetting(engine, container, func(cfg *InstanceConfig, probe string) { cfg.Password = probe })
+	}
+
+	if err := m.store.Save(dbConfig); err != nil {
+		return fmt.Errorf("failed to save database config: %w", err)
+	}
+
+	if err := engine.Ping(dbConfig); err != nil && container.State != nil && container.State.Running {
+		fmt.Printf("⚠️  Adopted '%s' but could not connect with the recovered credentials: %v\n", name, err)
+	}
+
+	fmt.Printf("✅ Container %s adopted as '%s'\n", shortContainerID(container.ID), name)
+	return nil
+}
+
+func lostContainer(issues []Issue, lost map[string]int, name, dbType string) bool {
+	i, ok := lost[name]
+	return ok && issues[i].Type == dbType
+}
+
+func (m *Manager) storedPassword(db *config.DatabaseConfig) string {
+	store, err := config.OpenSecrets()
+	if err != nil {
+		return ""
+	}
+
+	password, err := store.Get(secrets.DatabaseKey(db.Type, db.Name))
+	if err != nil {
+		return ""
+	}
+	return password
+}
+
+// recoverSetting finds where the engine puts one instance setting in the
+// container, by rendering it with a probe value, and reads the real value
+// back from the container's environment or command.
+func recoverSetting(engine Engine, container *types.ContainerJSON, set func(cfg *InstanceConfig, probe string)) string {
+	const probe = "spindb-reconcile-probe"
+
+	cfg := &InstanceConfig{Name: probe}
+	set(cfg, probe)
+
+	for _, env := range engine.Env(cfg) {
+		key, value, _ := strings.Cut(env, "=")
+		if value != probe {
+			continue
+		}
+		for _, actual := range container.Config.Env {
+			if actualKey, actualValue, _ := strings.Cut(actual, "="); actualKey == key {
+				return actualValue
+			}
+		}
+	}
+
+	if commander, ok := engine.(CommandEngine); ok {
+		for i, arg := range commander.Command(cfg) {
+			if arg == probe && i < len(container.Config.Cmd) {
+				return container.Config.Cmd[i]
+			}
+		}
+	}
+
+	return ""
+}
+
+// containerIdentity reads the engine and database name from the labels set
+// at creation, falling back to the spindb-<engine>-<name> container name for
+// containers created before the labels existed.
+func containerIdentity(container *types.ContainerJSON) (string, string) {
+	if container.Config != nil {
+		if dbType, name := container.Config.Labels[labelType], container.Config.Labels[labelName]; dbType != "" && name != "" {
+			return dbType, name
+		}
+	}
+
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(container.Name, "/"), "spindb-")
+	if !ok {
+		return "", ""
+	}
+
+	dbType, name, _ := strings.Cut(rest, "-")
+	return dbType, name
+}
+
+func dataMountSource(engine Engine, container *types.ContainerJSON) string {
+	for _, mount := range container.Mounts {
+		if mount.Destination == engine.DataMountPath() {
+			return mount.Source
+		}
+	}
+	return ""
+}
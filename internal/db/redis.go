@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/docker"
+)
+
+type RedisConfig = InstanceConfig
+
+type redisEngine struct{}
+
+const redisDumpPath = "/data/dump.rdb"
+
+func init() {
+	RegisterEngine(&redisEngine{})
+}
+
+func (e *redisEngine) Name() string        { return "redis" }
+func (e *redisEngine) DisplayName() string { return "Redis" }
+func (e *redisEngine) Containerized() bool { return true }
+
+func (e *redisEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{
+		Version: cfg.Default.Redis.Version,
+		Port:    cfg.Default.Redis.Port,
+	}
+}
+
+func (e *redisEngine) Image(version string) string {
+	return fmt.Sprintf("redis:%s", version)
+}
+
+func (e *redisEngine) ContainerPort() string { return "6379" }
+func (e *redisEngine) DataMountPath() string { return "/data" }
+
+func (e *redisEngine) Env(cfg *InstanceConfig) []string {
+	return nil
+}
+
+func (e *redisEngine) Command(cfg *InstanceConfig) []string {
+	return []string{
+		"redis-server",
+		"--requirepass", cfg.Password,
+		"--appendonly", "no",
+		"--save", "60", "1",
+	}
+}
+
+func (e *redisEngine) DSN(db *config.DatabaseConfig) string {
+	return e.URL("localhost", db)
+}
+
+func (e *redisEngine) URL(host string, db *config.DatabaseConfig) string {
+	u := &url.URL{
+		Scheme: "redis",
+		User:   url.UserPassword("", db.Password),
+		Host:   net.JoinHostPort(host, strconv.Itoa(db.Port)),
+		Path:   "/0",
+	}
+	return u.String()
+}
+
+func (e *redisEngine) EnvVars(host string, db *config.DatabaseConfig) []EnvVar {
+	return []EnvVar{
+		{"REDIS_URL", e.URL(host, db)},
+		{"REDIS_HOST", host},
+		{"REDIS_PORT", strconv.Itoa(db.Port)},
+		{"REDIS_PASSWORD", db.Password},
+	}
+}
+
+func (e *redisEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "redis-cli",
+		Args:    []string{"-h", host, "-p", strconv.Itoa(db.Port)},
+		Env:     []string{fmt.Sprintf("REDISCLI_AUTH=%s", db.Password)},
+		InstallHints: []string{
+			"Ubuntu/Debian: sudo apt update && sudo apt install redis-tools",
+			"CentOS/RHEL: sudo yum install redis",
+			"Fedora: sudo dnf install redis",
+			"macOS: brew install redis",
+			"Alpine: apk add redis",
+		},
+		DockerAlternative: fmt.Sprintf("docker run -it --rm %s redis-cli -h host.docker.internal -p %d -a %s",
+			e.Image(db.Version), db.Port, db.Password),
+	}
+}
+
+func (e *redisEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestRedis("localhost", db.Port, db.Password)
+}
+
+// TableCounts reports the number of keys, as Redis has no tables.
+func (e *redisEngine) TableCounts(db *config.DatabaseConfig) (map[string]int64, error) {
+	reply, err := NewConnectionTester().RedisCommand("localhost", db.Port, db.Password, "DBSIZE")
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := strconv.ParseInt(strings.TrimPrefix(reply, ":"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected DBSIZE reply: %s", reply)
+	}
+
+	return map[string]int64{"keys": keys}, nil
+}
+
+func (e *redisEngine) BackupExtension() string { return ".rdb" }
+
+func (e *redisEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	if options != nil && options.SchemaOnly {
+		return fmt.Errorf("schema-only backups are not supported for Redis")
+	}
+
+	if _, err := NewConnectionTester().RedisCommand("localhost", db.Port, db.Password, "SAVE"); err != nil {
+		return err
+	}
+
+	dockerSvc, err := docker.NewService()
+	if err != nil {
+		return err
+	}
+	defer dockerSvc.Close()
+
+	return dockerSvc.CopyFileFromContainer(context.Background(), db.ContainerID, redisDumpPath, w)
+}
+
+func (e *redisEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	dockerSvc, err := docker.NewService()
+	if err != nil {
+		return err
+	}
+	defer dockerSvc.Close()
+
+	tmpFile, err := os.CreateTemp("", "spindb-redis-*.rdb")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	size, err := io.Copy(tmpFile, r)
+	if err != nil {
+		return err
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// Redis only reads the RDB file at startup, and writes its own snapshot
+	// on shutdown, so the file has to be swapped while the server is down.
+	ctx := context.Background()
+	if err := dockerSvc.StopContainer(ctx, db.ContainerID); err != nil {
+		return fmt.Errorf("failed to stop container: %w", err)
+	}
+
+	if err := dockerSvc.CopyFileToContainer(ctx, db.ContainerID, redisDumpPath, tmpFile, size); err != nil {
+		dockerSvc.StartContainer(ctx, db.ContainerID)
+		return err
+	}
+
+	if err := dockerSvc.StartContainer(ctx, db.ContainerID); err != nil {
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+
+	deadline := time.Now().Add(60 * time.Second)
+	for {
+		err := e.Ping(db)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Redis did not come back after restore: %w", err)
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
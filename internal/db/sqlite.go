@@ -1,5 +1,106 @@
 package db
 
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/awade12/spindb/internal/config"
+)
+
 type SQLiteConfig struct {
 	FilePath string
 }
+
+type sqliteEngine struct{}
+
+func init() {
+	RegisterEngine(&sqliteEngine{})
+}
+
+func (e *sqliteEngine) Name() string        { return "sqlite" }
+func (e *sqliteEngine) DisplayName() string { return "SQLite" }
+func (e *sqliteEngine) Containerized() bool { return false }
+
+func (e *sqliteEngine) Defaults(cfg *config.Config) EngineDefaults {
+	return EngineDefaults{}
+}
+
+func (e *sqliteEngine) Image(version string) string          { return "" }
+func (e *sqliteEngine) ContainerPort() string                { return "" }
+func (e *sqliteEngine) DataMountPath() string                { return "" }
+func (e *sqliteEngine) Env(cfg *InstanceConfig) []string     { return nil }
+func (e *sqliteEngine) DSN(db *config.DatabaseConfig) string { return db.FilePath }
+
+func (e *sqliteEngine) URL(host string, db *config.DatabaseConfig) string {
+	path := db.FilePath
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return "file:" + filepath.ToSlash(path)
+}
+
+func (e *sqliteEngine) Client(host string, db *config.DatabaseConfig) *Client {
+	return &Client{
+		Command: "sqlite3",
+		Args:    []string{db.FilePath},
+		InstallHints: []string{
+			"Ubuntu/Debian: sudo apt update && sudo apt install sqlite3",
+			"CentOS/RHEL: sudo yum install sqlite",
+			"Fedora: sudo dnf install sqlite",
+			"macOS: brew install sqlite",
+			"Alpine: apk add sqlite",
+		},
+		DockerAlternative: fmt.Sprintf("docker run -it --rm -v %s:/db alpine sqlite3 /db", db.FilePath),
+	}
+}
+
+func (e *sqliteEngine) Ping(db *config.DatabaseConfig) error {
+	return NewConnectionTester().TestSQLite(db.FilePath)
+}
+
+func (e *sqliteEngine) RunScript(db *config.DatabaseConfig, r io.Reader) error {
+	script, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return NewConnectionTester().Exec("sqlite", db.FilePath, string(script))
+}
+
+func (e *sqliteEngine) TableCounts(db *config.DatabaseConfig) (map[string]int64, error) {
+	query := "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY 1"
+	return NewConnectionTester().TableCounts("sqlite", db.FilePath, query, func(table string) string {
+		return `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	})
+}
+
+func (e *sqliteEngine) Assert(db *config.DatabaseConfig, query string) (bool, error) {
+	return NewConnectionTester().QueryTruthy("sqlite", db.FilePath, query)
+}
+
+func (e *sqliteEngine) BackupExtension() string { return ".db" }
+
+func (e *sqliteEngine) Backup(db *config.DatabaseConfig, options *DumpOptions, w io.Writer) error {
+	srcFile, err := os.Open(db.FilePath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	_, err = io.Copy(w, srcFile)
+	return err
+}
+
+func (e *sqliteEngine) Restore(db *config.DatabaseConfig, r io.Reader) error {
+	dstFile, err := os.Create(db.FilePath)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	_, err = io.Copy(dstFile, r)
+	return err
+}
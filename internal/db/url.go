@@ -0,0 +1,57 @@
+package db
+
+import (
+	"strings"
+	"unicode"
+)
+
+// URL returns the connection URL of a database as reached from host.
+func (m *Manager) URL(name, host string) (string, error) {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return "", err
+	}
+
+	engine, err := GetEngine(target.Type)
+	if err != nil {
+		return "", err
+	}
+
+	return engine.URL(host, target), nil
+}
+
+// EnvVars returns DATABASE_URL followed by the variables the engine's own
+// clients read, each name prefixed with prefix.
+func (m *Manager) EnvVars(name, host, prefix string) ([]EnvVar, error) {
+	target, err := m.findDatabase(name)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := GetEngine(target.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := []EnvVar{{"DATABASE_URL", engine.URL(host, target)}}
+	if envEngine, ok := engine.(EnvVarEngine); ok {
+		vars = append(vars, envEngine.EnvVars(host, target)...)
+	}
+
+	for i := range vars {
+		vars[i].Name = prefix + vars[i].Name
+	}
+
+	return vars, nil
+}
+
+// EnvVarPrefix turns a database name into a variable name prefix, so
+// "orders-db" becomes "ORDERS_DB_".
+func EnvVarPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name) + "_"
+}
@@ -1,13 +1,21 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net"
+	"os"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/awade12/spindb/internal/utils"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -15,6 +23,7 @@ import (
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -63,9 +72,13 @@ func (s *Service) PullImage(ctx context.Context, imageName string) error {
 type ContainerConfig struct {
 	Name          string
 	Image         string
+	Cmd           []string
+	Healthcheck   []string
+	User          string
 	Env           []string
 	Ports         map[string]string
 	Volumes       []string
+	Labels        map[string]string
 	RestartPolicy string
 	Public        bool
 }
@@ -120,13 +133,29 @@ func (s *Service) CreateContainer(ctx context.Context, config *ContainerConfig)
 		}
 	}
 
+	labels := map[string]string{
+		"spindb": "true",
+	}
+	for key, value := range config.Labels {
+		labels[key] = value
+	}
+
 	containerConfig := &container.Config{
 		Image:        config.Image,
+		Cmd:          config.Cmd,
+		User:         config.User,
 		Env:          config.Env,
 		ExposedPorts: exposedPorts,
-		Labels: map[string]string{
-			"spindb": "true",
-		},
+		Labels:       labels,
+	}
+
+	if len(config.Healthcheck) > 0 {
+		containerConfig.Healthcheck = &container.HealthConfig{
+			Test:     config.Healthcheck,
+			Interval: 10 * time.Second,
+			Timeout:  5 * time.Second,
+			Retries:  5,
+		}
 	}
 
 	hostConfig := &container.HostConfig{
@@ -273,6 +302,256 @@ func (s *Service) ListSpinDBContainers(ctx context.Context) ([]types.ContainerJS
 	return containers, nil
 }
 
+// PublishedPort returns the host port bound to a container port and whether
+// it is bound on all interfaces rather than localhost.
+func PublishedPort(c *types.ContainerJSON, containerPort string) (int, bool, bool) {
+	if c.HostConfig == nil {
+		return 0, false, false
+	}
+
+	bindings := c.HostConfig.PortBindings[nat.Port(containerPort+"/tcp")]
+	if len(bindings) == 0 {
+		return 0, false, false
+	}
+
+	port, err := strconv.Atoi(bindings[0].HostPort)
+	if err != nil {
+		return 0, false, false
+	}
+
+	public := bindings[0].HostIP == "" || bindings[0].HostIP == "0.0.0.0"
+	return port, public, true
+}
+
+// Exec runs cmd inside a running container, streaming stdin to it and its
+// stdout to w. stderr is collected and returned in the error when the
+// command exits non-zero.
+func (s *Service) Exec(ctx context.Context, containerID string, cmd, env []string, stdin io.Reader, stdout io.Writer) error {
+	created, err := s.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          cmd,
+		Env:          env,
+		AttachStdin:  stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run %s in container: %w", cmd[0], err)
+	}
+
+	attach, err := s.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to attach to %s: %w", cmd[0], err)
+	}
+	defer attach.Close()
+
+	inputErr := make(chan error, 1)
+	if stdin != nil {
+		go func() {
+			_, err := io.Copy(attach.Conn, stdin)
+			attach.CloseWrite()
+			inputErr <- err
+		}()
+	} else {
+		inputErr <- nil
+	}
+
+	if stdout == nil {
+		stdout = io.Discard
+	}
+
+	var stderr bytes.Buffer
+	_, outputErr := stdcopy.StdCopy(stdout, &stderr, attach.Reader)
+
+	// Closing the connection unblocks the input copy if the command exited
+	// without reading all of it.
+	attach.Close()
+	copyErr := <-inputErr
+
+	if outputErr != nil {
+		return fmt.Errorf("failed to read %s output: %w", cmd[0], outputErr)
+	}
+
+	var inspect container.ExecInspect
+	for i := 0; i < 50; i++ {
+		inspect, err = s.client.ContainerExecInspect(ctx, created.ID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", cmd[0], err)
+		}
+		if !inspect.Running {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if inspect.Running {
+		return fmt.Errorf("%s did not exit", cmd[0])
+	}
+
+	if inspect.ExitCode != 0 {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%s exited with code %d: %s", cmd[0], inspect.ExitCode, message)
+		}
+		return fmt.Errorf("%s exited with code %d", cmd[0], inspect.ExitCode)
+	}
+
+	if copyErr != nil {
+		return fmt.Errorf("failed to send input to %s: %w", cmd[0], copyErr)
+	}
+
+	return nil
+}
+
+func (s *Service) CopyFileFromContainer(ctx context.Context, containerID, srcPath string, w io.Writer) error {
+	out, _, err := s.client.CopyFromContainer(ctx, containerID, srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to copy %s from container: %w", srcPath, err)
+	}
+	defer out.Close()
+
+	tr := tar.NewReader(out)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("file %s not found in container archive", srcPath)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read container archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if _, err := io.Copy(w, tr); err != nil {
+			return fmt.Errorf("failed to read %s from container: %w", srcPath, err)
+		}
+		return nil
+	}
+}
+
+func (s *Service) CopyFileToContainer(ctx context.Context, containerID, dstPath string, r io.Reader, size int64) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		tw := tar.NewWriter(pw)
+		header := &tar.Header{
+			Name:    path.Base(dstPath),
+			Mode:    0644,
+			Size:    size,
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(tw, r); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+
+	err := s.client.CopyToContainer(ctx, containerID, path.Dir(dstPath), pr, container.CopyToContainerOptions{})
+	pr.Close()
+	if err != nil {
+		return fmt.Errorf("failed to copy %s into container: %w", dstPath, err)
+	}
+
+	return nil
+}
+
+// RunTask runs cmd to completion in a throwaway root container with the
+// given bind mounts. It is used for file operations on data directories that
+// the database container has chowned away from the invoking user.
+func (s *Service) RunTask(ctx context.Context, imageName string, volumes []string, cmd []string) error {
+	mounts := []mount.Mount{}
+	for _, volume := range volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) >= 2 {
+			mounts = append(mounts, mount.Mount{
+				Type:   mount.TypeBind,
+				Source: parts[0],
+				Target: parts[1],
+			})
+		}
+	}
+
+	containerConfig := &container.Config{
+		Image:      imageName,
+		Entrypoint: cmd[:1],
+		Cmd:        cmd[1:],
+		User:       "root",
+	}
+
+	hostConfig := &container.HostConfig{
+		Mounts: mounts,
+	}
+
+	resp, err := s.client.ContainerCreate(ctx, containerConfig, hostConfig, &network.NetworkingConfig{}, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to create task container: %w", err)
+	}
+	defer s.client.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true})
+
+	statusCh, errCh := s.client.ContainerWait(ctx, resp.ID, container.WaitConditionNextExit)
+
+	if err := s.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start task container: %w", err)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to wait for task container: %w", err)
+	case status := <-statusCh:
+		if status.StatusCode != 0 {
+			logs, _ := s.GetContainerLogs(ctx, resp.ID, "20")
+			return fmt.Errorf("%s exited with status %d: %s", cmd[0], status.StatusCode, strings.TrimSpace(logs))
+		}
+	}
+
+	return nil
+}
+
+// CopyDataDir copies a database data directory, sharing blocks with the
+// source where the filesystem supports it. Database containers chown their
+// data directory to an in-container user, so when the invoking user cannot
+// read it the copy is redone as root in a container from the database image.
+func (s *Service) CopyDataDir(ctx context.Context, imageName, src, dst string) error {
+	err := utils.CopyDir(src, dst)
+	if err == nil || !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+
+	if err := s.RemoveDataDir(ctx, imageName, dst); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	return s.RunTask(ctx, imageName,
+		[]string{CreateVolumeMount(src, "/from"), CreateVolumeMount(dst, "/to")},
+		[]string{"sh", "-c", "cp -a --reflink=auto /from/. /to/ 2>/dev/null || cp -a /from/. /to/"},
+	)
+}
+
+func (s *Service) RemoveDataDir(ctx context.Context, imageName, path string) error {
+	err := os.RemoveAll(path)
+	if err == nil || !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+
+	err = s.RunTask(ctx, imageName,
+		[]string{CreateVolumeMount(path, "/target")},
+		[]string{"find", "/target", "-mindepth", "1", "-delete"},
+	)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(path)
+}
+
 func CreateVolumeMount(hostPath, containerPath string) string {
 	return hostPath + ":" + containerPath
 }
@@ -9,16 +9,20 @@ import (
 
 	"github.com/awade12/spindb/internal/config"
 	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
 type Environment struct {
-	Name        string                            `yaml:"name"`
-	Description string                            `yaml:"description"`
-	Active      bool                              `yaml:"active"`
-	Databases   map[string]*config.DatabaseConfig `yaml:"databases"`
-	CreatedAt   time.Time                         `yaml:"created_at"`
-	UpdatedAt   time.Time                         `yaml:"updated_at"`
+	SchemaVersion int                               `json:"schema_version" yaml:"schema_version"`
+	Name          string                            `json:"name" yaml:"name"`
+	Description   string                            `json:"description" yaml:"description"`
+	Active        bool                              `json:"active" yaml:"active"`
+	Databases     map[string]*config.DatabaseConfig `json:"databases" yaml:"databases"`
+	Retention     *config.RetentionPolicy           `json:"retention,omitempty" yaml:"retention,omitempty"`
+	Schedule      *config.BackupSchedule            `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	CreatedAt     time.Time                         `json:"created_at" yaml:"created_at"`
+	UpdatedAt     time.Time                         `json:"updated_at" yaml:"updated_at"`
 }
 
 type EnvironmentManager struct {
@@ -37,8 +41,7 @@ type BulkOperation struct {
 }
 
 func NewEnvironmentManager() *EnvironmentManager {
-	home, _ := os.UserHomeDir()
-	envDir := filepath.Join(home, ".spindb", "environments")
+	envDir := filepath.Join(utils.SpinDBHome(), "environments")
 	os.MkdirAll(envDir, 0755)
 
 	store := config.NewDatabaseStore()
@@ -55,6 +58,16 @@ func NewEnvironmentManager() *EnvironmentManager {
 }
 
 func (em *EnvironmentManager) CreateEnvironment(name, description string) error {
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return em.createEnvironment(name, description)
+}
+
+func (em *EnvironmentManager) createEnvironment(name, description string) error {
 	if em.EnvironmentExists(name) {
 		return fmt.Errorf("environment '%s' already exists", name)
 	}
@@ -76,6 +89,12 @@ func (em *EnvironmentManager) DeleteEnvironment(name string, force bool) error {
 		return fmt.Errorf("cannot delete the default environment")
 	}
 
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	if !em.EnvironmentExists(name) {
 		return fmt.Errorf("environment '%s' not found", name)
 	}
@@ -90,7 +109,7 @@ func (em *EnvironmentManager) DeleteEnvironment(name string, force bool) error {
 	}
 
 	if env.Active {
-		if err := em.SwitchEnvironment("default"); err != nil {
+		if err := em.switchEnvironment("default"); err != nil {
 			return fmt.Errorf("failed to switch to default environment: %w", err)
 		}
 	}
@@ -100,11 +119,21 @@ func (em *EnvironmentManager) DeleteEnvironment(name string, force bool) error {
 }
 
 func (em *EnvironmentManager) SwitchEnvironment(name string) error {
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return em.switchEnvironment(name)
+}
+
+func (em *EnvironmentManager) switchEnvironment(name string) error {
 	if !em.EnvironmentExists(name) {
 		if name != "default" {
 			return fmt.Errorf("environment '%s' not found", name)
 		}
-		if err := em.CreateEnvironment("default", "Default environment"); err != nil {
+		if err := em.createEnvironment("default", "Default environment"); err != nil {
 			return err
 		}
 	}
@@ -171,6 +200,12 @@ func (em *EnvironmentManager) LoadEnvironment(name string) (*Environment, error)
 }
 
 func (em *EnvironmentManager) AddDatabaseToEnvironment(envName, dbName string) error {
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	env, err := em.LoadEnvironment(envName)
 	if err != nil {
 		return err
@@ -199,7 +234,53 @@ func (em *EnvironmentManager) AddDatabaseToEnvironment(envName, dbName string) e
 	return em.saveEnvironment(env)
 }
 
+// SetRetention sets the backup retention policy of the environment's
+// databases. A nil policy removes it.
+func (em *EnvironmentManager) SetRetention(envName string, policy *config.RetentionPolicy) error {
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	env, err := em.LoadEnvironment(envName)
+	if err != nil {
+		return err
+	}
+
+	env.Retention = policy
+	env.UpdatedAt = time.Now()
+
+	return em.saveEnvironment(env)
+}
+
+// SetSchedule sets the backup schedule of the environment's databases. A
+// nil schedule removes it.
+func (em *EnvironmentManager) SetSchedule(envName string, schedule *config.BackupSchedule) error {
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	env, err := em.LoadEnvironment(envName)
+	if err != nil {
+		return err
+	}
+
+	env.Schedule = schedule
+	env.UpdatedAt = time.Now()
+
+	return em.saveEnvironment(env)
+}
+
 func (em *EnvironmentManager) RemoveDatabaseFromEnvironment(envName, dbName string) error {
+	lock, err := em.lock()
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	env, err := em.LoadEnvironment(envName)
 	if err != nil {
 		return err
@@ -327,12 +408,13 @@ func (em *EnvironmentManager) EnvironmentExists(name string) bool {
 func (em *EnvironmentManager) saveEnvironment(env *Environment) error {
 	envPath := filepath.Join(em.envDir, env.Name+".yaml")
 
+	env.SchemaVersion = config.SchemaVersion
 	data, err := yaml.Marshal(env)
 	if err != nil {
 		return fmt.Errorf("failed to marshal environment: %w", err)
 	}
 
-	return os.WriteFile(envPath, data, 0644)
+	return utils.WriteFileAtomic(envPath, data, 0600)
 }
 
 func (em *EnvironmentManager) getCurrentEnvironment() string {
@@ -353,7 +435,13 @@ func (em *EnvironmentManager) getCurrentEnvironment() string {
 
 func (em *EnvironmentManager) saveCurrentEnvironment(name string) error {
 	currentPath := filepath.Join(em.envDir, ".current")
-	return os.WriteFile(currentPath, []byte(name), 0644)
+	return utils.WriteFileAtomic(currentPath, []byte(name), 0644)
+}
+
+// lock serializes environment mutations across spindb processes. Callers
+// holding it must use the unexported variants that do not lock again.
+func (em *EnvironmentManager) lock() (*utils.FileLock, error) {
+	return utils.LockFile(filepath.Join(em.envDir, ".lock"))
 }
 
 func (em *EnvironmentManager) IsolateEnvironment(envName string) error {
@@ -0,0 +1,323 @@
+package manifest
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/environment"
+)
+
+const (
+	ActionCreate = "create"
+	ActionStart  = "start"
+	ActionJoin   = "join"
+	ActionDrift  = "drift"
+	ActionExtra  = "extra"
+	ActionNone   = "none"
+)
+
+// Change is one difference between the manifest and what SpinDB manages.
+// Drift and extra changes are reported but never applied by Up, because
+// fixing them means recreating or deleting a database.
+type Change struct {
+	Database string `json:"database" yaml:"database"`
+	Engine   string `json:"engine" yaml:"engine"`
+	Action   string `json:"action" yaml:"action"`
+	Detail   string `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+type Manager struct {
+	dbManager  *db.Manager
+	envManager *environment.EnvironmentManager
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		dbManager:  db.NewManager(),
+		envManager: environment.NewEnvironmentManager(),
+	}
+}
+
+func (m *Manager) Diff(manifest *Manifest) ([]Change, error) {
+	databases, err := m.dbManager.ListDatabases("", false)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]db.DatabaseInfo)
+	for _, info := range databases {
+		existing[info.Name] = info
+	}
+
+	members, err := m.environmentMembers(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	for _, database := range manifest.Databases {
+		info, ok := existing[database.Name]
+		if !ok {
+			changes = append(changes, Change{database.Name, database.Engine, ActionCreate, describe(database)})
+			if manifest.Environment != "" {
+				changes = append(changes, Change{database.Name, database.Engine, ActionJoin, fmt.Sprintf("add to environment '%s'", manifest.Environment)})
+			}
+			continue
+		}
+
+		drift := compare(database, info)
+		for _, detail := range drift {
+			changes = append(changes, Change{database.Name, database.Engine, ActionDrift, detail})
+		}
+
+		if info.Type == database.Engine && info.Status == db.StatusStopped {
+			changes = append(changes, Change{database.Name, database.Engine, ActionStart, "stopped"})
+		}
+
+		if manifest.Environment != "" && !members[database.Name] {
+			changes = append(changes, Change{database.Name, database.Engine, ActionJoin, fmt.Sprintf("add to environment '%s'", manifest.Environment)})
+		}
+
+		if len(drift) == 0 && info.Status != db.StatusStopped && (manifest.Environment == "" || members[database.Name]) {
+			changes = append(changes, Change{database.Name, database.Engine, ActionNone, "up to date"})
+		}
+	}
+
+	var extras []string
+	for name := range members {
+		if !slices.ContainsFunc(manifest.Databases, func(d Database) bool { return d.Name == name }) {
+			extras = append(extras, name)
+		}
+	}
+	sort.Strings(extras)
+	for _, name := range extras {
+		changes = append(changes, Change{name, existing[name].Type, ActionExtra, fmt.Sprintf("in environment '%s' but not in the manifest", manifest.Environment)})
+	}
+
+	return changes, nil
+}
+
+// Up creates or starts whatever the manifest declares that is missing or
+// stopped and adds every database to the manifest's environment.
+func (m *Manager) Up(manifest *Manifest) error {
+	changes, err := m.Diff(manifest)
+	if err != nil {
+		return err
+	}
+
+	declared := make(map[string]Database)
+	for _, database := range manifest.Databases {
+		declared[database.Name] = database
+	}
+
+	applied := 0
+	for _, change := range changes {
+		switch change.Action {
+		case ActionCreate:
+			if err := m.create(declared[change.Database]); err != nil {
+				return fmt.Errorf("failed to create '%s': %w", change.Database, err)
+			}
+			applied++
+		case ActionStart:
+			if err := m.dbManager.Start(change.Database); err != nil {
+				return err
+			}
+			applied++
+		case ActionJoin:
+			if err := m.join(manifest.Environment, change.Database); err != nil {
+				return err
+			}
+			applied++
+		case ActionDrift:
+			fmt.Printf("⚠️  %s: %s (run 'spindb down' and 'spindb up' to recreate it)\n", change.Database, change.Detail)
+		case ActionExtra:
+			fmt.Printf("ℹ️  %s: %s\n", change.Database, change.Detail)
+		}
+	}
+
+	if applied == 0 {
+		fmt.Println("✅ Everything in the manifest is already up.")
+		return nil
+	}
+
+	fmt.Printf("✅ Project is up (%d changes applied).\n", applied)
+	return nil
+}
+
+// Down deletes every database the manifest declares, and with removeData
+// their data as well. Kept data keeps its password, so the next Up brings
+// the same database back. The environment is deleted once it is empty.
+func (m *Manager) Down(manifest *Manifest, removeData bool) error {
+	databases, err := m.dbManager.ListDatabases("", false)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool)
+	for _, info := range databases {
+		existing[info.Name] = true
+	}
+
+	members, err := m.environmentMembers(manifest)
+	if err != nil {
+		return err
+	}
+
+	for i := len(manifest.Databases) - 1; i >= 0; i-- {
+		name := manifest.Databases[i].Name
+
+		if members[name] {
+			if err := m.envManager.RemoveDatabaseFromEnvironment(manifest.Environment, name); err != nil {
+				return err
+			}
+		}
+
+		if !existing[name] {
+			fmt.Printf("Database '%s' does not exist, skipping.\n", name)
+			continue
+		}
+
+		if err := m.dbManager.Remove(name, removeData); err != nil {
+			return fmt.Errorf("failed to remove '%s': %w", name, err)
+		}
+	}
+
+	if manifest.Environment != "" && manifest.Environment != "default" && m.envManager.EnvironmentExists(manifest.Environment) {
+		env, err := m.envManager.LoadEnvironment(manifest.Environment)
+		if err != nil {
+			return err
+		}
+		if len(env.Databases) == 0 {
+			if err := m.envManager.DeleteEnvironment(manifest.Environment, false); err != nil {
+				return fmt.Errorf("failed to delete environment: %w", err)
+			}
+			fmt.Printf("✅ Environment '%s' deleted.\n", manifest.Environment)
+		}
+	}
+
+	fmt.Println("✅ Project is down.")
+	return nil
+}
+
+func (m *Manager) create(database Database) error {
+	engine, err := db.GetEngine(database.Engine)
+	if err != nil {
+		return err
+	}
+
+	cfg := &db.InstanceConfig{
+		Name:       database.Name,
+		User:       database.User,
+		Password:   database.Password,
+		Port:       database.Port,
+		Version:    database.Version,
+		Public:     database.Public,
+		FilePath:   database.File,
+		Extensions: database.Extensions,
+	}
+
+	// Init scripts already ran against a data directory kept by down.
+	reused := engine.Containerized() && m.dbManager.HasData(engine.Name(), database.Name)
+
+	if err := m.dbManager.Create(engine.Name(), cfg); err != nil {
+		return err
+	}
+
+	if reused && len(database.Init) > 0 {
+		fmt.Printf("Skipping init scripts, '%s' kept its data\n", database.Name)
+		return nil
+	}
+
+	for _, script := range database.Init {
+		fmt.Printf("Running init script %s...\n", script)
+		if err := m.dbManager.RunScript(database.Name, script); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) join(envName, dbName string) error {
+	if !m.envManager.EnvironmentExists(envName) {
+		if err := m.envManager.CreateEnvironment(envName, "Created from spindb.yaml"); err != nil {
+			return fmt.Errorf("failed to create environment: %w", err)
+		}
+		fmt.Printf("✅ Environment '%s' created.\n", envName)
+	}
+
+	if err := m.envManager.AddDatabaseToEnvironment(envName, dbName); err != nil {
+		return fmt.Errorf("failed to add '%s' to environment: %w", dbName, err)
+	}
+
+	fmt.Printf("✅ Database '%s' added to environment '%s'.\n", dbName, envName)
+	return nil
+}
+
+func (m *Manager) environmentMembers(manifest *Manifest) (map[string]bool, error) {
+	members := make(map[string]bool)
+	if manifest.Environment == "" || !m.envManager.EnvironmentExists(manifest.Environment) {
+		return members, nil
+	}
+
+	env, err := m.envManager.LoadEnvironment(manifest.Environment)
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range env.Databases {
+		members[name] = true
+	}
+
+	return members, nil
+}
+
+func describe(database Database) string {
+	parts := []string{database.Engine}
+	if database.Version != "" {
+		parts = append(parts, database.Version)
+	}
+	if database.Port != 0 {
+		parts = append(parts, fmt.Sprintf("port %d", database.Port))
+	}
+	if len(database.Extensions) > 0 {
+		parts = append(parts, "with "+strings.Join(database.Extensions, ", "))
+	}
+	return strings.Join(parts, " ")
+}
+
+// compare lists the settings that differ between a declared database and
+// the registered one. Unset manifest fields do not count as drift.
+func compare(database Database, info db.DatabaseInfo) []string {
+	if info.Type != database.Engine {
+		return []string{fmt.Sprintf("engine is %s, manifest wants %s", info.Type, database.Engine)}
+	}
+
+	var drift []string
+	if database.Version != "" && database.Version != info.Version {
+		drift = append(drift, fmt.Sprintf("version is %s, manifest wants %s", info.Version, database.Version))
+	}
+	if database.Port != 0 && database.Port != info.Port {
+		drift = append(drift, fmt.Sprintf("port is %d, manifest wants %d", info.Port, database.Port))
+	}
+	if info.Containerized && database.Public != info.Public {
+		drift = append(drift, fmt.Sprintf("public is %t, manifest wants %t", info.Public, database.Public))
+	}
+	if database.User != "" && info.User != "" && database.User != info.User {
+		drift = append(drift, fmt.Sprintf("user is %s, manifest wants %s", info.User, database.User))
+	}
+	if database.File != "" && database.File != info.FilePath {
+		drift = append(drift, fmt.Sprintf("file is %s, manifest wants %s", info.FilePath, database.File))
+	}
+
+	want := slices.Sorted(slices.Values(database.Extensions))
+	have := slices.Sorted(slices.Values(info.Extensions))
+	if !slices.Equal(want, have) {
+		drift = append(drift, fmt.Sprintf("extensions are [%s], manifest wants [%s]", strings.Join(have, ", "), strings.Join(want, ", ")))
+	}
+
+	return drift
+}
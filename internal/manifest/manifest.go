@@ -0,0 +1,184 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultFile = "spindb.yaml"
+	Version     = 1
+)
+
+// Manifest is a spindb.yaml checked into a project, declaring the databases
+// it needs. ${VAR} references in values are expanded from the environment on
+// load, so passwords can stay out of the file.
+type Manifest struct {
+	Version     int        `yaml:"version"`
+	Environment string     `yaml:"environment,omitempty"`
+	Databases   []Database `yaml:"databases"`
+
+	path string
+}
+
+type Database struct {
+	Name       string   `yaml:"name"`
+	Engine     string   `yaml:"engine"`
+	Version    string   `yaml:"version,omitempty"`
+	Port       int      `yaml:"port,omitempty"`
+	User       string   `yaml:"user,omitempty"`
+	Password   string   `yaml:"password,omitempty"`
+	Public     bool     `yaml:"public,omitempty"`
+	Extensions []string `yaml:"extensions,omitempty"`
+	File       string   `yaml:"file,omitempty"`
+	Init       []string `yaml:"init,omitempty"`
+}
+
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	if err := expandEnv(&doc); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	var manifest Manifest
+	if err := doc.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	manifest.path = path
+
+	if err := manifest.validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, err)
+	}
+
+	return &manifest, nil
+}
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces ${VAR} in the values of the document. Keys, comments
+// and any other $ are left as written, and an unset variable is an error
+// rather than an empty string.
+func expandEnv(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var missing []string
+		expanded := envReference.ReplaceAllStringFunc(node.Value, func(ref string) string {
+			name := envReference.FindStringSubmatch(ref)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+		if len(missing) > 0 {
+			return fmt.Errorf("line %d: environment variable %s is not set", node.Line, strings.Join(missing, ", "))
+		}
+		if expanded != node.Value && node.Style == 0 {
+			// Let a plain ${PORT} resolve to the type of what it expands to.
+			node.Tag = ""
+		}
+		node.Value = expanded
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := expandEnv(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			if err := expandEnv(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Manifest) Path() string {
+	return m.path
+}
+
+// resolve makes a path from the manifest relative to the manifest's
+// directory rather than the working directory.
+func (m *Manifest) resolve(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(m.path), path)
+}
+
+func (m *Manifest) validate() error {
+	if m.Version == 0 {
+		m.Version = Version
+	}
+	if m.Version > Version {
+		return fmt.Errorf("manifest version %d is newer than this spindb supports (%d)", m.Version, Version)
+	}
+
+	if m.Environment != "" {
+		if err := utils.ValidateDatabaseName(m.Environment); err != nil {
+			return fmt.Errorf("environment: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range m.Databases {
+		database := &m.Databases[i]
+
+		engine, err := db.GetEngine(database.Engine)
+		if err != nil {
+			return fmt.Errorf("database %d: %w", i+1, err)
+		}
+
+		// SQLite databases are registered under the name of their file.
+		if !engine.Containerized() {
+			if database.File == "" {
+				return fmt.Errorf("database %d: %s databases need a file", i+1, engine.DisplayName())
+			}
+			database.File = m.resolve(database.File)
+			if database.Name == "" {
+				database.Name = filepath.Base(database.File)
+			}
+			if database.Name != filepath.Base(database.File) {
+				return fmt.Errorf("database '%s': %s databases are named after their file, use '%s'", database.Name, engine.DisplayName(), filepath.Base(database.File))
+			}
+		} else if err := utils.ValidateDatabaseName(database.Name); err != nil {
+			return fmt.Errorf("database %d: %w", i+1, err)
+		}
+
+		if seen[database.Name] {
+			return fmt.Errorf("database '%s' is declared twice", database.Name)
+		}
+		seen[database.Name] = true
+
+		if _, ok := engine.(db.ScriptEngine); !ok && len(database.Init) > 0 {
+			return fmt.Errorf("database '%s': init scripts are not supported for %s", database.Name, engine.DisplayName())
+		}
+
+		for j, script := range database.Init {
+			database.Init[j] = m.resolve(script)
+		}
+	}
+
+	return nil
+}
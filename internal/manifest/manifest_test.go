@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadExpandsEnv(t *testing.T) {
+	t.Setenv("SPINDB_TEST_PASSWORD", "s3cret")
+	t.Setenv("SPINDB_TEST_PORT", "5433")
+	t.Setenv("SPINDB_TEST_EMPTY", "")
+
+	tests := []struct {
+		name     string
+		database string
+		want     Database
+		wantErr  string
+	}{
+		{
+			name:     "braced reference",
+			database: "password: ${SPINDB_TEST_PASSWORD}",
+			want:     Database{Password: "s3cret"},
+		},
+		{
+			name:     "reference inside a value",
+			database: "password: pre-${SPINDB_TEST_PASSWORD}-post",
+			want:     Database{Password: "pre-s3cret-post"},
+		},
+		{
+			name:     "unbraced reference is left alone",
+			database: "password: $SPINDB_TEST_PASSWORD",
+			want:     Database{Password: "$SPINDB_TEST_PASSWORD"},
+		},
+		{
+			name:     "lone dollar is left alone",
+			database: "password: pa$$word$",
+			want:     Database{Password: "pa$$word$"},
+		},
+		{
+			name:     "set but empty variable",
+			database: "password: ${SPINDB_TEST_EMPTY}",
+			want:     Database{Password: ""},
+		},
+		{
+			name:     "plain reference takes the type of its value",
+			database: "port: ${SPINDB_TEST_PORT}",
+			want:     Database{Port: 5433},
+		},
+		{
+			name:     "quoted reference stays a string",
+			database: "user: \"${SPINDB_TEST_PORT}\"",
+			want:     Database{User: "5433"},
+		},
+		{
+			name:     "references in lists",
+			database: "extensions: [\"${SPINDB_TEST_PASSWORD}\"]",
+			want:     Database{Extensions: []string{"s3cret"}},
+		},
+		{
+			name:     "unset variable",
+			database: "password: ${SPINDB_TEST_UNSET}",
+			wantErr:  "environment variable SPINDB_TEST_UNSET is not set",
+		},
+		{
+			name:     "unset variable is reported with its line",
+			database: "user: app\n    password: ${SPINDB_TEST_UNSET}",
+			wantErr:  "line 7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFile)
+			content := "version: 1\n" +
+				"# ${SPINDB_TEST_UNSET} in a comment is not expanded\n" +
+				"databases:\n" +
+				"  - name: app\n" +
+				"    engine: postgres\n" +
+				"    " + tt.database + "\n"
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			manifest, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load = %v, want an error mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			got := manifest.Databases[0]
+			if got.Password != tt.want.Password || got.Port != tt.want.Port || got.User != tt.want.User ||
+				strings.Join(got.Extensions, ",") != strings.Join(tt.want.Extensions, ",") {
+				t.Errorf("database = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDoesNotExpandKeys(t *testing.T) {
+	t.Setenv("SPINDB_TEST_KEY", "name")
+
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	content := "version: 1\ndatabases:\n  - ${SPINDB_TEST_KEY}: app\n    name: app\n    engine: redis\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := manifest.Databases[0].Name; got != "app" {
+		t.Errorf("name = %q, want app", got)
+	}
+}
@@ -0,0 +1,75 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretService stores secrets in the freedesktop Secret Service (GNOME
+// Keyring, KWallet) through libsecret's secret-tool.
+type secretService struct {
+	cache map[string]string
+}
+
+func newSecretService() (*secretService, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, fmt.Errorf("secret-service backend needs secret-tool (Ubuntu/Debian: sudo apt install libsecret-tools, Fedora: sudo dnf install libsecret)")
+	}
+
+	return &secretService{cache: map[string]string{}}, nil
+}
+
+func (s *secretService) Name() string { return "secret-service" }
+
+func (s *secretService) Get(key string) (string, error) {
+	if value, ok := s.cache[key]; ok {
+		return value, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", "spindb", "key", key)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.TrimSpace(stderr.String()) == "" {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("secret-tool lookup failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	value := strings.TrimSuffix(string(out), "\n")
+	s.cache[key] = value
+	return value, nil
+}
+
+func (s *secretService) Set(key, value string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "store", "--label", "SpinDB "+key, "service", "spindb", "key", key)
+	cmd.Stdin = strings.NewReader(value)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool store failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	s.cache[key] = value
+	return nil
+}
+
+func (s *secretService) Delete(key string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "clear", "service", "spindb", "key", key)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("secret-tool clear failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	delete(s.cache, key)
+	return nil
+}
@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps credentials out of the YAML state files. The registry,
+// environments and templates only hold the key a secret is stored under.
+type Store interface {
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+type Options struct {
+	Home    string
+	KeyFile string
+}
+
+func Open(backend string, opts Options) (Store, error) {
+	switch backend {
+	case "", "vault":
+		return newVault(opts), nil
+	case "secret-service":
+		return newSecretService()
+	default:
+		return nil, fmt.Errorf("unknown secrets backend: %s (expected vault or secret-service)", backend)
+	}
+}
+
+func DatabaseKey(dbType, name string) string {
+	return fmt.Sprintf("databases/%s/%s/password", dbType, name)
+}
+
+func TemplateKey(name string) string {
+	return fmt.Sprintf("templates/%s/password", name)
+}
@@ -0,0 +1,281 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/awade12/spindb/internal/utils"
+	"golang.org/x/crypto/scrypt"
+)
+
+const PassphraseEnv = "SPINDB_VAULT_PASSPHRASE"
+
+const (
+	kdfKeyFile = "keyfile"
+	kdfScrypt  = "scrypt"
+)
+
+var vaultAAD = []byte("spindb-vault-v1")
+
+// vault is an AES-256-GCM encrypted file holding every secret. The key comes
+// from SPINDB_VAULT_PASSPHRASE through scrypt when the vault was created with
+// a passphrase, and from a random key file otherwise.
+type vault struct {
+	path    string
+	keyFile string
+	entries map[string]string
+	// loaded describes the vault file entries was read from, nil when there
+	// was none yet.
+	loaded os.FileInfo
+}
+
+type vaultFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func newVault(opts Options) *vault {
+	keyFile := opts.KeyFile
+	if keyFile == "" {
+		keyFile = filepath.Join(opts.Home, "vault.key")
+	}
+
+	return &vault{
+		path:    filepath.Join(opts.Home, "secrets.vault"),
+		keyFile: keyFile,
+	}
+}
+
+func (v *vault) Name() string { return "vault" }
+
+func (v *vault) Get(key string) (string, error) {
+	if err := v.refresh(); err != nil {
+		return "", err
+	}
+
+	value, ok := v.entries[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+func (v *vault) Set(key, value string) error {
+	return v.update(func(entries map[string]string) {
+		entries[key] = value
+	})
+}
+
+func (v *vault) Delete(key string) error {
+	return v.update(func(entries map[string]string) {
+		delete(entries, key)
+	})
+}
+
+func (v *vault) update(fn func(entries map[string]string)) error {
+	if err := os.MkdirAll(filepath.Dir(v.path), 0755); err != nil {
+		return fmt.Errorf("failed to create vault directory: %w", err)
+	}
+
+	lock, err := utils.LockFile(v.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	entries, header, err := v.load()
+	if err != nil {
+		return err
+	}
+
+	fn(entries)
+
+	if err := v.save(entries, header); err != nil {
+		return err
+	}
+
+	v.entries = entries
+	v.loaded, _ = os.Stat(v.path)
+	return nil
+}
+
+// refresh reads the vault again when another process replaced it since it
+// was read, so a long-running agent sees passwords rotated meanwhile.
+func (v *vault) refresh() error {
+	info, err := os.Stat(v.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read vault: %w", err)
+	}
+	if v.entries != nil && sameVaultFile(v.loaded, info) {
+		return nil
+	}
+
+	entries, _, err := v.load()
+	if err != nil {
+		return err
+	}
+
+	v.entries = entries
+	v.loaded = info
+	return nil
+}
+
+// sameVaultFile reports whether two stats describe the same vault file.
+// Writes replace the file, so any write changes its identity.
+func sameVaultFile(a, b os.FileInfo) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return os.SameFile(a, b) && a.ModTime().Equal(b.ModTime()) && a.Size() == b.Size()
+}
+
+func (v *vault) load() (map[string]string, *vaultFile, error) {
+	data, err := os.ReadFile(v.path)
+	if os.IsNotExist(err) {
+		header, err := v.newHeader()
+		return map[string]string{}, header, err
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read vault: %w", err)
+	}
+
+	var header vaultFile
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse vault %s: %w", v.path, err)
+	}
+
+	gcm, err := v.cipher(&header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, header.Nonce, header.Ciphertext, vaultAAD)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unlock vault %s: wrong key or passphrase", v.path)
+	}
+
+	entries := map[string]string{}
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse vault contents: %w", err)
+	}
+
+	return entries, &header, nil
+}
+
+func (v *vault) save(entries map[string]string, header *vaultFile) error {
+	gcm, err := v.cipher(header)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault contents: %w", err)
+	}
+
+	header.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(header.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	header.Ciphertext = gcm.Seal(nil, header.Nonce, plaintext, vaultAAD)
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(v.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+
+	return nil
+}
+
+func (v *vault) newHeader() (*vaultFile, error) {
+	header := &vaultFile{Version: 1, KDF: kdfKeyFile}
+
+	if os.Getenv(PassphraseEnv) != "" {
+		header.KDF = kdfScrypt
+		header.Salt = make([]byte, 16)
+		if _, err := rand.Read(header.Salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	return header, nil
+}
+
+func (v *vault) cipher(header *vaultFile) (cipher.AEAD, error) {
+	key, err := v.key(header)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (v *vault) key(header *vaultFile) ([]byte, error) {
+	switch header.KDF {
+	case kdfScrypt:
+		passphrase := os.Getenv(PassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("vault %s is passphrase protected, set %s to unlock it", v.path, PassphraseEnv)
+		}
+		return scrypt.Key([]byte(passphrase), header.Salt, 1<<15, 8, 1, 32)
+	case kdfKeyFile:
+		return v.readOrCreateKeyFile()
+	default:
+		return nil, fmt.Errorf("unsupported vault key derivation: %s", header.KDF)
+	}
+}
+
+func (v *vault) readOrCreateKeyFile() ([]byte, error) {
+	data, err := os.ReadFile(v.keyFile)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("vault key file %s is not a 32-byte hex key", v.keyFile)
+		}
+		return key, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read vault key file: %w", err)
+	}
+
+	// A missing key file is only expected before the vault exists; otherwise
+	// generating a new key would lock every stored secret away for good.
+	if _, err := os.Stat(v.path); err == nil {
+		return nil, fmt.Errorf("vault key file %s not found", v.keyFile)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate vault key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(v.keyFile), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	if err := utils.WriteFileAtomic(v.keyFile, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write vault key file: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,169 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVaultRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+		wantKDF    string
+	}{
+		{"key file", "", kdfKeyFile},
+		{"passphrase", "correct horse battery staple", kdfScrypt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PassphraseEnv, tt.passphrase)
+			home := t.TempDir()
+
+			v := newVault(Options{Home: home})
+			if _, err := v.Get("missing"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get on a new vault = %v, want ErrNotFound", err)
+			}
+
+			for key, value := range map[string]string{
+				DatabaseKey("postgres", "app"): "app-password",
+				TemplateKey("web"):             "web-password",
+			} {
+				if err := v.Set(key, value); err != nil {
+					t.Fatalf("Set(%s): %v", key, err)
+				}
+			}
+
+			// A fresh vault reads everything back from disk.
+			reopened := newVault(Options{Home: home})
+			if got, err := reopened.Get(DatabaseKey("postgres", "app")); err != nil || got != "app-password" {
+				t.Fatalf("Get = %q, %v, want app-password", got, err)
+			}
+
+			if err := reopened.Delete(TemplateKey("web")); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := newVault(Options{Home: home}).Get(TemplateKey("web")); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+			}
+
+			_, header, err := reopened.load()
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if header.KDF != tt.wantKDF {
+				t.Errorf("KDF = %s, want %s", header.KDF, tt.wantKDF)
+			}
+
+			data, err := os.ReadFile(filepath.Join(home, "secrets.vault"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), "app-password") {
+				t.Error("vault file holds the password in plaintext")
+			}
+
+			_, err = os.Stat(filepath.Join(home, "vault.key"))
+			if hasKeyFile := err == nil; hasKeyFile != (tt.passphrase == "") {
+				t.Errorf("key file exists = %t, want %t", hasKeyFile, tt.passphrase == "")
+			}
+		})
+	}
+}
+
+func TestVaultWrongPassphrase(t *testing.T) {
+	home := t.TempDir()
+
+	t.Setenv(PassphraseEnv, "right")
+	if err := newVault(Options{Home: home}).Set("key", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		passphrase string
+		wantErr    string
+	}{
+		{"wrong", "wrong", "wrong key or passphrase"},
+		{"unset", "", PassphraseEnv},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(PassphraseEnv, tt.passphrase)
+
+			v := newVault(Options{Home: home})
+			if _, err := v.Get("key"); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Get = %v, want an error mentioning %q", err, tt.wantErr)
+			}
+			if err := v.Set("key", "other"); err == nil {
+				t.Error("Set succeeded, want an error")
+			}
+		})
+	}
+
+	t.Setenv(PassphraseEnv, "right")
+	if got, err := newVault(Options{Home: home}).Get("key"); err != nil || got != "value" {
+		t.Errorf("Get with the right passphrase = %q, %v, want value", got, err)
+	}
+}
+
+func TestVaultKeyFile(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	home := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "custom.key")
+
+	if err := newVault(Options{Home: home, KeyFile: keyFile}).Set("key", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	// Another key must not open the vault.
+	other := newVault(Options{Home: t.TempDir()})
+	if err := other.Set("key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	wrongKey := newVault(Options{Home: home, KeyFile: other.keyFile})
+	if _, err := wrongKey.Get("key"); err == nil {
+		t.Error("Get with another vault's key succeeded, want an error")
+	}
+
+	// Losing the key file must not silently create a new one.
+	if err := os.Remove(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newVault(Options{Home: home, KeyFile: keyFile}).Get("key"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Get without the key file = %v, want a key file not found error", err)
+	}
+	if _, err := os.Stat(keyFile); err == nil {
+		t.Error("a new key file was generated for an existing vault")
+	}
+}
+
+func TestVaultSeesChangesFromOtherProcesses(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	home := t.TempDir()
+
+	agent := newVault(Options{Home: home})
+	if _, err := agent.Get("key"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before the vault exists = %v, want ErrNotFound", err)
+	}
+
+	// Each vault stands for another spindb process, like credentials rotate
+	// running while the agent is up.
+	for _, value := range []string{"first", "rotated", "rotated again"} {
+		if err := newVault(Options{Home: home}).Set("key", value); err != nil {
+			t.Fatalf("Set(%s): %v", value, err)
+		}
+		if got, err := agent.Get("key"); err != nil || got != value {
+			t.Fatalf("Get = %q, %v, want %q", got, err, value)
+		}
+	}
+
+	if err := newVault(Options{Home: home}).Delete("key"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := agent.Get("key"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after another process deleted the key = %v, want ErrNotFound", err)
+	}
+}
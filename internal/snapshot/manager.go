@@ -0,0 +1,321 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/awade12/spindb/internal/config"
+	"github.com/awade12/spindb/internal/db"
+	"github.com/awade12/spindb/internal/docker"
+	"github.com/awade12/spindb/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	metadataFile = "snapshot.yaml"
+	dataSubdir   = "data"
+)
+
+type SnapshotManager struct {
+	dataDir     string
+	snapshotDir string
+	store       *config.DatabaseStore
+}
+
+type SnapshotInfo struct {
+	Name      string    `json:"name" yaml:"name"`
+	Database  string    `json:"database" yaml:"database"`
+	Type      string    `json:"type" yaml:"type"`
+	Version   string    `json:"version,omitempty" yaml:"version,omitempty"`
+	Size      int64     `json:"size" yaml:"size"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+	Path      string    `json:"-" yaml:"-"`
+}
+
+func NewSnapshotManager() *SnapshotManager {
+	cfg := config.Load()
+
+	return &SnapshotManager{
+		dataDir:     cfg.Storage.DataDir,
+		snapshotDir: cfg.Storage.SnapshotDir,
+		store:       config.NewDatabaseStore(),
+	}
+}
+
+func (sm *SnapshotManager) CreateSnapshot(dbName, snapshotName string) (*SnapshotInfo, error) {
+	if snapshotName == "" {
+		snapshotName = time.Now().Format("20060102_150405")
+	}
+
+	if err := utils.ValidateSnapshotName(snapshotName); err != nil {
+		return nil, err
+	}
+
+	database, engine, err := sm.findDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotPath := sm.snapshotPath(database, snapshotName)
+	if _, err := os.Stat(snapshotPath); err == nil {
+		return nil, fmt.Errorf("snapshot '%s' already exists for database '%s'", snapshotName, dbName)
+	}
+
+	if err := os.MkdirAll(snapshotPath, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	dockerSvc, err := docker.NewService()
+	if err != nil {
+		return nil, err
+	}
+	defer dockerSvc.Close()
+
+	ctx := context.Background()
+	image := sm.taskImage(dockerSvc, engine, database)
+
+	err = sm.whileStopped(dockerSvc, engine, database, func() error {
+		return dockerSvc.CopyDataDir(ctx, image, sm.databaseDataDir(database), filepath.Join(snapshotPath, dataSubdir))
+	})
+	if err != nil {
+		dockerSvc.RemoveDataDir(ctx, image, snapshotPath)
+		return nil, err
+	}
+
+	size, _ := utils.DirSize(filepath.Join(snapshotPath, dataSubdir))
+
+	info := &SnapshotInfo{
+		Name:      snapshotName,
+		Database:  database.Name,
+		Type:      database.Type,
+		Version:   database.Version,
+		Size:      size,
+		CreatedAt: time.Now(),
+		Path:      snapshotPath,
+	}
+
+	data, err := yaml.Marshal(info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot metadata: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(snapshotPath, metadataFile), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+
+	return info, nil
+}
+
+func (sm *SnapshotManager) ListSnapshots(dbName string) ([]*SnapshotInfo, error) {
+	var dirs []string
+
+	if dbName != "" {
+		database, _, err := sm.findDatabase(dbName)
+		if err != nil {
+			return nil, err
+		}
+		dirs = []string{filepath.Join(sm.snapshotDir, database.Type, database.Name)}
+	} else {
+		matches, err := filepath.Glob(filepath.Join(sm.snapshotDir, "*", "*"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		dirs = matches
+	}
+
+	var snapshots []*SnapshotInfo
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			info, err := readMetadata(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				continue
+			}
+
+			snapshots = append(snapshots, info)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+func (sm *SnapshotManager) RestoreSnapshot(dbName, snapshotName string) error {
+	if err := utils.ValidateSnapshotName(snapshotName); err != nil {
+		return err
+	}
+
+	database, engine, err := sm.findDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	snapshotPath := sm.snapshotPath(database, snapshotName)
+	if _, err := readMetadata(snapshotPath); err != nil {
+		return fmt.Errorf("snapshot '%s' not found for database '%s'", snapshotName, dbName)
+	}
+
+	dockerSvc, err := docker.NewService()
+	if err != nil {
+		return err
+	}
+	defer dockerSvc.Close()
+
+	ctx := context.Background()
+	image := sm.taskImage(dockerSvc, engine, database)
+	dataDir := sm.databaseDataDir(database)
+
+	return sm.whileStopped(dockerSvc, engine, database, func() error {
+		// Keep the current data until the snapshot has been copied in full, so
+		// a failed copy leaves the database as it was.
+		previous := dataDir + ".pre-restore"
+		if err := dockerSvc.RemoveDataDir(ctx, image, previous); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", previous, err)
+		}
+
+		if err := os.Rename(dataDir, previous); err != nil {
+			return fmt.Errorf("failed to move current data aside: %w", err)
+		}
+
+		if err := dockerSvc.CopyDataDir(ctx, image, filepath.Join(snapshotPath, dataSubdir), dataDir); err != nil {
+			dockerSvc.RemoveDataDir(ctx, image, dataDir)
+			os.Rename(previous, dataDir)
+			return fmt.Errorf("failed to restore snapshot data: %w", err)
+		}
+
+		return dockerSvc.RemoveDataDir(ctx, image, previous)
+	})
+}
+
+func (sm *SnapshotManager) DeleteSnapshot(dbName, snapshotName string) error {
+	if err := utils.ValidateSnapshotName(snapshotName); err != nil {
+		return err
+	}
+
+	database, engine, err := sm.findDatabase(dbName)
+	if err != nil {
+		return err
+	}
+
+	snapshotPath := sm.snapshotPath(database, snapshotName)
+	if _, err := readMetadata(snapshotPath); err != nil {
+		return fmt.Errorf("snapshot '%s' not found for database '%s'", snapshotName, dbName)
+	}
+
+	dockerSvc, err := docker.NewService()
+	if err != nil {
+		return err
+	}
+	defer dockerSvc.Close()
+
+	return dockerSvc.RemoveDataDir(context.Background(), sm.taskImage(dockerSvc, engine, database), snapshotPath)
+}
+
+func (sm *SnapshotManager) findDatabase(name string) (*config.DatabaseConfig, db.Engine, error) {
+	databases, err := sm.store.List("")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load databases: %w", err)
+	}
+
+	for _, database := range databases {
+		if database.Name != name {
+			continue
+		}
+
+		engine, err := db.GetEngine(database.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !engine.Containerized() {
+			return nil, nil, fmt.Errorf("snapshots are only supported for Docker databases, use 'spindb backup' for %s", engine.DisplayName())
+		}
+
+		if database.ContainerID == "" {
+			return nil, nil, fmt.Errorf("no container ID found for database '%s'", name)
+		}
+
+		return &database, engine, nil
+	}
+
+	return nil, nil, fmt.Errorf("database '%s' not found", name)
+}
+
+func (sm *SnapshotManager) databaseDataDir(database *config.DatabaseConfig) string {
+	return filepath.Join(sm.dataDir, database.Type, database.Name)
+}
+
+func (sm *SnapshotManager) snapshotPath(database *config.DatabaseConfig, snapshotName string) string {
+	return filepath.Join(sm.snapshotDir, database.Type, database.Name, snapshotName)
+}
+
+// whileStopped runs fn with the database container stopped, so the data
+// directory is consistent on disk, and brings it back up afterwards if it
+// was running before.
+func (sm *SnapshotManager) whileStopped(dockerSvc *docker.Service, engine db.Engine, database *config.DatabaseConfig, fn func() error) error {
+	ctx := context.Background()
+
+	running, err := dockerSvc.IsContainerRunning(ctx, database.ContainerID)
+	if err != nil {
+		return fmt.Errorf("failed to check container status: %w", err)
+	}
+
+	if running {
+		fmt.Printf("Stopping database '%s'...\n", database.Name)
+		if err := dockerSvc.StopContainer(ctx, database.ContainerID); err != nil {
+			return fmt.Errorf("failed to stop container: %w", err)
+		}
+	}
+
+	fnErr := fn()
+
+	if running {
+		fmt.Printf("Starting database '%s'...\n", database.Name)
+		if err := dockerSvc.StartContainer(ctx, database.ContainerID); err != nil {
+			return errors.Join(fnErr, fmt.Errorf("failed to start container: %w", err))
+		}
+		if err := db.WaitForEngine(engine, database, 60*time.Second); err != nil {
+			return errors.Join(fnErr, err)
+		}
+	}
+
+	return fnErr
+}
+
+func (sm *SnapshotManager) taskImage(dockerSvc *docker.Service, engine db.Engine, database *config.DatabaseConfig) string {
+	if container, err := dockerSvc.GetContainer(context.Background(), database.ContainerID); err == nil {
+		return container.Config.Image
+	}
+	return engine.Image(database.Version)
+}
+
+func readMetadata(snapshotPath string) (*SnapshotInfo, error) {
+	data, err := os.ReadFile(filepath.Join(snapshotPath, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var info SnapshotInfo
+	if err := yaml.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot metadata: %w", err)
+	}
+	info.Path = snapshotPath
+
+	return &info, nil
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyDir recursively copies src to dst, keeping file modes and, where the
+// current user is allowed to, ownership. Files are reflinked when the
+// filesystem supports it and copied otherwise. dst must not exist yet.
+func CopyDir(src, dst string) error {
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("destination %s already exists", dst)
+	}
+
+	// Directory modes are applied once the walk is done so that read-only
+	// directories can still be filled.
+	dirModes := map[string]fs.FileMode{}
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			if err := os.Mkdir(target, 0700); err != nil {
+				return err
+			}
+			dirModes[target] = info.Mode().Perm()
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		case info.Mode().IsRegular():
+			if err := copyFile(path, target, info.Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			// Sockets and pipes left behind by a stopped server are not data.
+			return nil
+		}
+
+		copyOwner(target, info)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for dir, mode := range dirModes {
+		if err := os.Chmod(dir, mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	if err := cloneFile(src, dst, perm); err == nil {
+		return nil
+	} else if errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func DirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// DirHasData reports whether path is a directory with anything in it. A
+// directory the caller cannot read, typically one a database container has
+// chowned, counts as holding data.
+func DirHasData(path string) (bool, error) {
+	entries, err := os.ReadDir(path)
+	switch {
+	case os.IsNotExist(err):
+		return false, nil
+	case errors.Is(err, fs.ErrPermission):
+		return true, nil
+	case err != nil:
+		return false, err
+	}
+	return len(entries) > 0, nil
+}
+
+// WriteFileAtomic replaces path with data by writing a temp file in the same
+// directory and renaming it over path, so readers never see a partial file.
+func WriteFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
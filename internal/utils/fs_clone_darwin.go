@@ -0,0 +1,18 @@
+//go:build darwin
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile uses APFS clonefile(2), so the copy shares blocks with src until
+// either side writes.
+func cloneFile(src, dst string, perm os.FileMode) error {
+	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW); err != nil {
+		return err
+	}
+	return os.Chmod(dst, perm)
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile creates dst as a reflink of src on filesystems that support it
+// (btrfs, XFS, bcachefs), so the copy shares blocks until either side writes.
+func cloneFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if err := unix.IoctlFileClone(int(out.Fd()), int(in.Fd())); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+func cloneFile(src, dst string, perm os.FileMode) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package utils
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+func copyOwner(path string, info fs.FileInfo) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		os.Lchown(path, int(stat.Uid), int(stat.Gid))
+	}
+}
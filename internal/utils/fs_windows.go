@@ -0,0 +1,7 @@
+//go:build windows
+
+package utils
+
+import "io/fs"
+
+func copyOwner(path string, info fs.FileInfo) {}
@@ -15,13 +15,22 @@ func EnsureDir(path string) error {
 	return nil
 }
 
-func GetSpinDBHome() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+// SpinDBHome resolves the directory holding all SpinDB state: SPINDB_HOME
+// when set (the --home flag sets it too), otherwise ~/.spindb.
+func SpinDBHome() string {
+	if spindbHome := os.Getenv("SPINDB_HOME"); spindbHome != "" {
+		if abs, err := filepath.Abs(spindbHome); err == nil {
+			return abs
+		}
+		return spindbHome
 	}
 
-	spindbHome := filepath.Join(home, ".spindb")
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".spindb")
+}
+
+func GetSpinDBHome() (string, error) {
+	spindbHome := SpinDBHome()
 	if err := EnsureDir(spindbHome); err != nil {
 		return "", fmt.Errorf("failed to create SpinDB home directory: %w", err)
 	}
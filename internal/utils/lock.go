@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrLocked is returned by TryLockFile when another process holds the lock.
+var ErrLocked = errors.New("lock is held by another process")
+
+type FileLock struct {
+	file *os.File
+}
+
+// LockFile takes an exclusive advisory lock on path, creating it if needed,
+// and blocks until the lock is free. Locks belong to the open file, so a
+// process must not lock the same path again while it holds it.
+func LockFile(path string) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	return &FileLock{file: file}, nil
+}
+
+// TryLockFile is LockFile without waiting: it returns ErrLocked when the
+// lock is taken.
+func TryLockFile(path string) (*FileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	locked, err := tryLockFile(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+	if !locked {
+		file.Close()
+		return nil, ErrLocked
+	}
+
+	return &FileLock{file: file}, nil
+}
+
+func (l *FileLock) Unlock() error {
+	unlockFile(l.file)
+	return l.file.Close()
+}
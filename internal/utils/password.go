@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	passwordLength  = 24
+	passwordLower   = "abcdefghijkmnopqrstuvwxyz"
+	passwordUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordDigits  = "23456789"
+	passwordCharset = passwordLower + passwordUpper + passwordDigits
+)
+
+// GeneratePassword returns a random password that is safe to embed in URLs,
+// DSNs and shell commands and that satisfies the SQL Server complexity rules.
+func GeneratePassword() (string, error) {
+	for {
+		var b strings.Builder
+		for i := 0; i < passwordLength; i++ {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(passwordCharset))))
+			if err != nil {
+				return "", fmt.Errorf("failed to generate password: %w", err)
+			}
+			b.WriteByte(passwordCharset[n.Int64()])
+		}
+
+		password := b.String()
+		if strings.ContainsAny(password, passwordLower) &&
+			strings.ContainsAny(password, passwordUpper) &&
+			strings.ContainsAny(password, passwordDigits) {
+			return password, nil
+		}
+	}
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"regexp"
+	"unicode"
 )
 
 func ValidateDatabaseName(name string) error {
@@ -28,3 +29,53 @@ func ValidatePort(port int) error {
 	}
 	return nil
 }
+
+func ValidateMSSQLPassword(password string) error {
+	if len(password) < 8 {
+		return fmt.Errorf("SQL Server password must be at least 8 characters long")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	categories := 0
+	for _, present := range []bool{upper, lower, digit, symbol} {
+		if present {
+			categories++
+		}
+	}
+
+	if categories < 3 {
+		return fmt.Errorf("SQL Server password must contain characters from at least three of: uppercase letters, lowercase letters, digits, symbols")
+	}
+
+	return nil
+}
+
+func ValidateSnapshotName(name string) error {
+	if name == "" {
+		return fmt.Errorf("snapshot name cannot be empty")
+	}
+
+	matched, err := regexp.MatchString("^[a-zA-Z0-9_-]+$", name)
+	if err != nil {
+		return err
+	}
+
+	if !matched {
+		return fmt.Errorf("snapshot name can only contain alphanumeric characters, hyphens, and underscores")
+	}
+
+	return nil
+}